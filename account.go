@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// AccountIdentity is the partition and account of the credentials in use,
+// needed to build ARNs for services that only return resource names.
+type AccountIdentity struct {
+	Partition string
+	AccountId string
+}
+
+var CurrentAccount *AccountIdentity
+
+func GetAccountIdentity(sess *session.Session) (*AccountIdentity, error) {
+	// GetCallerIdentity is only called once per run.
+	if CurrentAccount != nil {
+		return CurrentAccount, nil
+	}
+	result, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+	CallerArn, err := arn.Parse(aws.StringValue(result.Arn))
+	if err != nil {
+		return nil, err
+	}
+	CurrentAccount = &AccountIdentity{
+		Partition: CallerArn.Partition,
+		AccountId: aws.StringValue(result.Account),
+	}
+	return CurrentAccount, nil
+}
+
+func (Account *AccountIdentity) Arn(Service, Region, Resource string) string {
+	return arn.ARN{
+		Partition: Account.Partition,
+		Service:   Service,
+		Region:    Region,
+		AccountID: Account.AccountId,
+		Resource:  Resource,
+	}.String()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+)
+
+func ACMCertificateFinder(svc *acm.ACM, CertificateList []*acm.CertificateSummary, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Certificate := range CertificateList {
+		result, err := svc.ListTagsForCertificate(&acm.ListTagsForCertificateInput{
+			CertificateArn: Certificate.CertificateArn,
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Certificate.CertificateArn, err)
+			continue
+		}
+		Tags := make(map[string]string)
+		for _, Tag := range result.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "aws-acm-certificate",
+			ID:        aws.StringValue(Certificate.DomainName),
+			Arn:       *Certificate.CertificateArn,
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Certificate.CreatedAt),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func ACMCertificateInit(PolicyObject *Policy, sess *session.Session) {
+	svc := acm.New(sess)
+	var Certificates []*acm.CertificateSummary
+	err := svc.ListCertificatesPages(&acm.ListCertificatesInput{}, func(page *acm.ListCertificatesOutput, lastPage bool) bool {
+		Certificates = append(Certificates, page.CertificateSummaryList...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load ACM Certificates %v\n", err)
+		return
+	}
+	if len(Certificates) == 0 {
+		fmt.Printf("No ACM Certificates for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := ACMCertificateFinder(svc, Certificates, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
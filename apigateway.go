@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+)
+
+// GetApiGatewayArn is the ARN of an API Gateway resource path such as
+// /restapis/a1b2c3d4e5, these ARNs have no account.
+func GetApiGatewayArn(Region, Path string) string {
+	return arn.ARN{
+		Partition: GetPartition(Region),
+		Service:   "apigateway",
+		Region:    Region,
+		Resource:  Path,
+	}.String()
+}
+
+func ListRestApis(svc *apigateway.APIGateway) []*apigateway.RestApi {
+	var RestApis []*apigateway.RestApi
+	err := svc.GetRestApisPages(&apigateway.GetRestApisInput{}, func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+		RestApis = append(RestApis, page.Items...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load REST APIs %v\n", err)
+	}
+	return RestApis
+}
+
+func ListHttpApis(svc *apigatewayv2.ApiGatewayV2) []*apigatewayv2.Api {
+	// GetApis has no paginator in the SDK, follow NextToken by hand.
+	var Apis []*apigatewayv2.Api
+	input := &apigatewayv2.GetApisInput{}
+	for {
+		result, err := svc.GetApis(input)
+		if err != nil {
+			fmt.Printf("Unable to load HTTP APIs %v\n", err)
+			break
+		}
+		Apis = append(Apis, result.Items...)
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+	return Apis
+}
+
+func RestApiFinder(svc *apigateway.APIGateway, RestApiList []*apigateway.RestApi, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, RestApi := range RestApiList {
+		Resources = append(Resources, Resource{
+			Type:      "apigateway-restapi",
+			ID:        *RestApi.Id,
+			Arn:       GetApiGatewayArn(*svc.Config.Region, "/restapis/"+*RestApi.Id),
+			Tags:      aws.StringValueMap(RestApi.Tags),
+			CreatedAt: aws.TimeValue(RestApi.CreatedDate),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func RestApiInit(PolicyObject *Policy, sess *session.Session) {
+	svc := apigateway.New(sess)
+	RestApis := ListRestApis(svc)
+	if len(RestApis) == 0 {
+		fmt.Printf("No REST APIs for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := RestApiFinder(svc, RestApis, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func HttpApiFinder(svc *apigatewayv2.ApiGatewayV2, ApiList []*apigatewayv2.Api, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Api := range ApiList {
+		Resources = append(Resources, Resource{
+			Type:      "apigatewayv2-api",
+			ID:        *Api.ApiId,
+			Arn:       GetApiGatewayArn(*svc.Config.Region, "/apis/"+*Api.ApiId),
+			Tags:      aws.StringValueMap(Api.Tags),
+			CreatedAt: aws.TimeValue(Api.CreatedDate),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func HttpApiInit(PolicyObject *Policy, sess *session.Session) {
+	svc := apigatewayv2.New(sess)
+	Apis := ListHttpApis(svc)
+	if len(Apis) == 0 {
+		fmt.Printf("No HTTP APIs for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := HttpApiFinder(svc, Apis, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func ApiStageInit(PolicyObject *Policy, sess *session.Session) {
+	// Stages of both REST APIs and HTTP/WebSocket APIs.
+	svc := apigateway.New(sess)
+	svcv2 := apigatewayv2.New(sess)
+	var Resources []Resource
+	for _, RestApi := range ListRestApis(svc) {
+		result, err := svc.GetStages(&apigateway.GetStagesInput{
+			RestApiId: RestApi.Id,
+		})
+		if err != nil {
+			fmt.Printf("Unable to load Stages for %s %v\n", *RestApi.Id, err)
+			continue
+		}
+		for _, Stage := range result.Item {
+			Resources = append(Resources, Resource{
+				Type:      "apigateway-stage",
+				ID:        *RestApi.Id + "/" + aws.StringValue(Stage.StageName),
+				Arn:       GetApiGatewayArn(*svc.Config.Region, "/restapis/"+*RestApi.Id+"/stages/"+aws.StringValue(Stage.StageName)),
+				Tags:      aws.StringValueMap(Stage.Tags),
+				CreatedAt: aws.TimeValue(Stage.CreatedDate),
+			})
+		}
+	}
+	for _, Api := range ListHttpApis(svcv2) {
+		input := &apigatewayv2.GetStagesInput{
+			ApiId: Api.ApiId,
+		}
+		for {
+			result, err := svcv2.GetStages(input)
+			if err != nil {
+				fmt.Printf("Unable to load Stages for %s %v\n", *Api.ApiId, err)
+				break
+			}
+			for _, Stage := range result.Items {
+				Resources = append(Resources, Resource{
+					Type:      "apigateway-stage",
+					ID:        *Api.ApiId + "/" + aws.StringValue(Stage.StageName),
+					Arn:       GetApiGatewayArn(*svc.Config.Region, "/apis/"+*Api.ApiId+"/stages/"+aws.StringValue(Stage.StageName)),
+					Tags:      aws.StringValueMap(Stage.Tags),
+					CreatedAt: aws.TimeValue(Stage.CreatedDate),
+				})
+			}
+			if result.NextToken == nil {
+				break
+			}
+			input.NextToken = result.NextToken
+		}
+	}
+	if len(Resources) == 0 {
+		fmt.Printf("No API Stages for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := ResourceFinder(Resources, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
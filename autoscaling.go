@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func AutoScalingGroupFinder(GroupList []*autoscaling.Group, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Group := range GroupList {
+		// DescribeAutoScalingGroups already returns the group's tags.
+		Tags := make(map[string]string)
+		for _, Tag := range Group.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "autoscaling-group",
+			ID:        *Group.AutoScalingGroupName,
+			Arn:       aws.StringValue(Group.AutoScalingGroupARN),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Group.CreatedTime),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func AutoScalingGroupInit(PolicyObject *Policy, sess *session.Session) {
+	svc := autoscaling.New(sess)
+	var Groups []*autoscaling.Group
+	err := svc.DescribeAutoScalingGroupsPages(&autoscaling.DescribeAutoScalingGroupsInput{}, func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
+		Groups = append(Groups, page.AutoScalingGroups...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Auto Scaling Groups %v\n", err)
+		return
+	}
+	if len(Groups) == 0 {
+		fmt.Printf("No Auto Scaling Groups for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := AutoScalingGroupFinder(Groups, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func LaunchTemplateFinder(LaunchTemplateList []*ec2.LaunchTemplate, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, LaunchTemplate := range LaunchTemplateList {
+		Tags := make(map[string]string)
+		for _, Tag := range LaunchTemplate.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "ec2-launchtemplate",
+			ID:        *LaunchTemplate.LaunchTemplateId,
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(LaunchTemplate.CreateTime),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func LaunchTemplateInit(PolicyObject *Policy, sess *session.Session) {
+	svc := ec2.New(sess)
+	var LaunchTemplates []*ec2.LaunchTemplate
+	err := svc.DescribeLaunchTemplatesPages(&ec2.DescribeLaunchTemplatesInput{}, func(page *ec2.DescribeLaunchTemplatesOutput, lastPage bool) bool {
+		LaunchTemplates = append(LaunchTemplates, page.LaunchTemplates...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Launch Templates %v\n", err)
+		return
+	}
+	if len(LaunchTemplates) == 0 {
+		fmt.Printf("No Launch Templates for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := LaunchTemplateFinder(LaunchTemplates, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
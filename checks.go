@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+)
+
+// CustomCheck is a rule a key list can't express, written as a CEL
+// expression evaluated locally against the resource. The expression returns
+// true when the resource passes, or a list of violation messages, empty when
+// it passes:
+//
+//	checks:
+//	- name: owner-email
+//	  expression: '!has(tags.Owner) || tags.Owner.endsWith("@example.com")'
+//	  message: "Owner must be an example.com email address"
+//	  severity: high
+//	- name: name-prefix
+//	  expression: '!has(tags.Environment) || tags.Name.startsWith(tags.Environment + "-")'
+//
+// The expression sees the variables resource_type, id, arn, region and
+// account as strings, and tags and attributes as maps of strings.
+type CustomCheck struct {
+	Name       string `yaml:"name"`
+	Expression string `yaml:"expression"`
+	Message    string `yaml:"message,omitempty"`
+	Severity   string `yaml:"severity,omitempty"`
+}
+
+var CheckEnv *cel.Env
+
+var stringSliceType = reflect.TypeOf([]string{})
+
+// CheckPrograms caches the compiled expressions, each is compiled once per run.
+var CheckPrograms = make(map[string]cel.Program)
+
+func (Rule PolicyRule) GetCheckSeverity(Check CustomCheck) string {
+	if Check.Severity == "" {
+		return Rule.GetSeverity()
+	}
+	return Check.Severity
+}
+
+func GetCheckEnv() (*cel.Env, error) {
+	if CheckEnv != nil {
+		return CheckEnv, nil
+	}
+	Env, err := cel.NewEnv(
+		cel.Variable("resource_type", cel.StringType),
+		cel.Variable("id", cel.StringType),
+		cel.Variable("arn", cel.StringType),
+		cel.Variable("region", cel.StringType),
+		cel.Variable("account", cel.StringType),
+		cel.Variable("tags", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("attributes", cel.MapType(cel.StringType, cel.StringType)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	CheckEnv = Env
+	return CheckEnv, nil
+}
+
+func (Check CustomCheck) Program() (cel.Program, error) {
+	if Program, ok := CheckPrograms[Check.Expression]; ok {
+		return Program, nil
+	}
+	Env, err := GetCheckEnv()
+	if err != nil {
+		return nil, err
+	}
+	Ast, issues := Env.Compile(Check.Expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if !Ast.OutputType().IsAssignableType(cel.BoolType) && !Ast.OutputType().IsAssignableType(cel.ListType(cel.StringType)) {
+		return nil, fmt.Errorf("expression returns %v, not bool or list(string)", Ast.OutputType())
+	}
+	Program, err := Env.Program(Ast)
+	if err != nil {
+		return nil, err
+	}
+	CheckPrograms[Check.Expression] = Program
+	return Program, nil
+}
+
+// Evaluate returns the check's violations of the resource. An expression that
+// fails, e.g. on a missing tag, is a violation too.
+func (Check CustomCheck) Evaluate(Resource Resource) []string {
+	Program, err := Check.Program()
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", Check.Name, err)}
+	}
+	Tags, Attributes := Resource.Tags, Resource.Attributes
+	if Tags == nil {
+		Tags = map[string]string{}
+	}
+	if Attributes == nil {
+		Attributes = map[string]string{}
+	}
+	Output, _, err := Program.Eval(map[string]interface{}{
+		"resource_type": Resource.Type,
+		"id":            Resource.ID,
+		"arn":           Resource.Arn,
+		"region":        Resource.Region,
+		"account":       Resource.Account,
+		"tags":          Tags,
+		"attributes":    Attributes,
+	})
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", Check.Name, err)}
+	}
+	if Output.Type() == types.BoolType {
+		if Output.Value().(bool) {
+			return nil
+		}
+		Message := Check.Message
+		if Message == "" {
+			Message = "failed"
+		}
+		return []string{Check.Name + ": " + Message}
+	}
+	Messages, err := Output.ConvertToNative(stringSliceType)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", Check.Name, err)}
+	}
+	var Violations []string
+	for _, Message := range Messages.([]string) {
+		Violations = append(Violations, Check.Name+": "+Message)
+	}
+	return Violations
+}
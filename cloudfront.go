@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+)
+
+// CloudFront is a global service: distributions are listed once per account
+// through GetGlobalResources, whichever region the session uses.
+
+func GetCloudFrontTags(svc *cloudfront.CloudFront, ResourceArn *string) (map[string]string, error) {
+	result, err := svc.ListTagsForResource(&cloudfront.ListTagsForResourceInput{
+		Resource: ResourceArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	Tags := make(map[string]string)
+	for _, Tag := range result.Tags.Items {
+		Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+	}
+	return Tags, nil
+}
+
+func ListCloudFrontDistributions(svc *cloudfront.CloudFront) []Resource {
+	var Distributions []*cloudfront.DistributionSummary
+	err := svc.ListDistributionsPages(&cloudfront.ListDistributionsInput{}, func(page *cloudfront.ListDistributionsOutput, lastPage bool) bool {
+		Distributions = append(Distributions, page.DistributionList.Items...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load CloudFront Distributions %v\n", err)
+		return nil
+	}
+	var Resources []Resource
+	for _, Distribution := range Distributions {
+		Tags, err := GetCloudFrontTags(svc, Distribution.ARN)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Distribution.Id, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "cloudfront:distribution",
+			ID:   *Distribution.Id,
+			Arn:  aws.StringValue(Distribution.ARN),
+			Tags: Tags,
+		})
+	}
+	return Resources
+}
+
+func ListCloudFrontStreamingDistributions(svc *cloudfront.CloudFront) []Resource {
+	var Distributions []*cloudfront.StreamingDistributionSummary
+	err := svc.ListStreamingDistributionsPages(&cloudfront.ListStreamingDistributionsInput{}, func(page *cloudfront.ListStreamingDistributionsOutput, lastPage bool) bool {
+		Distributions = append(Distributions, page.StreamingDistributionList.Items...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load CloudFront Streaming Distributions %v\n", err)
+		return nil
+	}
+	var Resources []Resource
+	for _, Distribution := range Distributions {
+		Tags, err := GetCloudFrontTags(svc, Distribution.ARN)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Distribution.Id, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "cloudfront:streamingdistribution",
+			ID:   *Distribution.Id,
+			Arn:  aws.StringValue(Distribution.ARN),
+			Tags: Tags,
+		})
+	}
+	return Resources
+}
+
+func CloudFrontDistributionInit(PolicyObject *Policy, sess *session.Session) {
+	Resources := GetGlobalResources("cloudfront:distribution", func() []Resource {
+		return ListCloudFrontDistributions(cloudfront.New(sess))
+	})
+	Tagged, UnTagged := ResourceFinder(Resources, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func CloudFrontStreamingDistributionInit(PolicyObject *Policy, sess *session.Session) {
+	Resources := GetGlobalResources("cloudfront:streamingdistribution", func() []Resource {
+		return ListCloudFrontStreamingDistributions(cloudfront.New(sess))
+	})
+	Tagged, UnTagged := ResourceFinder(Resources, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+)
+
+// CloudTrailListTagsLimit is the maximum number of trail ARNs accepted by a
+// single ListTags call.
+const CloudTrailListTagsLimit = 20
+
+func CloudTrailFinder(svc *cloudtrail.CloudTrail, TrailList []*cloudtrail.Trail, Rule PolicyRule) ([]string, []string) {
+	var TrailArnList []*string
+	for _, Trail := range TrailList {
+		TrailArnList = append(TrailArnList, Trail.TrailARN)
+	}
+	TagMap := make(map[string]map[string]string)
+	Failed := make(map[string]bool)
+	for start := 0; start < len(TrailArnList); start += CloudTrailListTagsLimit {
+		end := start + CloudTrailListTagsLimit
+		if end > len(TrailArnList) {
+			end = len(TrailArnList)
+		}
+		input := &cloudtrail.ListTagsInput{
+			ResourceIdList: TrailArnList[start:end],
+		}
+		err := svc.ListTagsPages(input, func(page *cloudtrail.ListTagsOutput, lastPage bool) bool {
+			for _, ResourceTag := range page.ResourceTagList {
+				Tags := make(map[string]string)
+				for _, Tag := range ResourceTag.TagsList {
+					Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+				}
+				TagMap[aws.StringValue(ResourceTag.ResourceId)] = Tags
+			}
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for Trails %v\n", err)
+			for _, TrailArn := range TrailArnList[start:end] {
+				Failed[*TrailArn] = true
+			}
+		}
+	}
+
+	var Resources []Resource
+	for _, Trail := range TrailList {
+		if Failed[*Trail.TrailARN] {
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "cloudtrail",
+			ID:   *Trail.Name,
+			Arn:  *Trail.TrailARN,
+			Tags: TagMap[*Trail.TrailARN],
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func CloudTrailInit(PolicyObject *Policy, sess *session.Session) {
+	svc := cloudtrail.New(sess)
+	result, err := svc.DescribeTrails(&cloudtrail.DescribeTrailsInput{
+		IncludeShadowTrails: aws.Bool(true),
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Trails %v\n", err)
+		return
+	}
+	// A multi-region or organization trail shows up in every region, only
+	// check it from its home region so it is reported once.
+	var Trails []*cloudtrail.Trail
+	for _, Trail := range result.TrailList {
+		if aws.StringValue(Trail.HomeRegion) != aws.StringValue(svc.Config.Region) {
+			continue
+		}
+		Trails = append(Trails, Trail)
+	}
+	if len(Trails) == 0 {
+		fmt.Printf("No Trails for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := CloudTrailFinder(svc, Trails, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+func GetCloudWatchTags(svc *cloudwatch.CloudWatch, ResourceArn *string) (map[string]string, error) {
+	result, err := svc.ListTagsForResource(&cloudwatch.ListTagsForResourceInput{
+		ResourceARN: ResourceArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	Tags := make(map[string]string)
+	for _, Tag := range result.Tags {
+		Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+	}
+	return Tags, nil
+}
+
+func CloudWatchAlarmFinder(svc *cloudwatch.CloudWatch, AlarmArns, AlarmNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for index, AlarmArn := range AlarmArns {
+		Tags, err := GetCloudWatchTags(svc, AlarmArn)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *AlarmNames[index], err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "cloudwatch-alarm",
+			ID:   *AlarmNames[index],
+			Arn:  *AlarmArn,
+			Tags: Tags,
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func CloudWatchAlarmInit(PolicyObject *Policy, sess *session.Session) {
+	svc := cloudwatch.New(sess)
+	// Metric and composite alarms.
+	var AlarmArns, AlarmNames []*string
+	input := &cloudwatch.DescribeAlarmsInput{
+		AlarmTypes: aws.StringSlice([]string{cloudwatch.AlarmTypeMetricAlarm, cloudwatch.AlarmTypeCompositeAlarm}),
+	}
+	err := svc.DescribeAlarmsPages(input, func(page *cloudwatch.DescribeAlarmsOutput, lastPage bool) bool {
+		for _, Alarm := range page.MetricAlarms {
+			AlarmArns = append(AlarmArns, Alarm.AlarmArn)
+			AlarmNames = append(AlarmNames, Alarm.AlarmName)
+		}
+		for _, Alarm := range page.CompositeAlarms {
+			AlarmArns = append(AlarmArns, Alarm.AlarmArn)
+			AlarmNames = append(AlarmNames, Alarm.AlarmName)
+		}
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load CloudWatch Alarms %v\n", err)
+		return
+	}
+	if len(AlarmArns) == 0 {
+		fmt.Printf("No CloudWatch Alarms for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := CloudWatchAlarmFinder(svc, AlarmArns, AlarmNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func MetricStreamFinder(svc *cloudwatch.CloudWatch, MetricStreamList []*cloudwatch.MetricStreamEntry, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, MetricStream := range MetricStreamList {
+		Tags, err := GetCloudWatchTags(svc, MetricStream.Arn)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *MetricStream.Name, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type:      "cloudwatch-metricstream",
+			ID:        *MetricStream.Name,
+			Arn:       aws.StringValue(MetricStream.Arn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(MetricStream.CreationDate),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func MetricStreamInit(PolicyObject *Policy, sess *session.Session) {
+	svc := cloudwatch.New(sess)
+	var MetricStreams []*cloudwatch.MetricStreamEntry
+	err := svc.ListMetricStreamsPages(&cloudwatch.ListMetricStreamsInput{}, func(page *cloudwatch.ListMetricStreamsOutput, lastPage bool) bool {
+		MetricStreams = append(MetricStreams, page.Entries...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Metric Streams %v\n", err)
+		return
+	}
+	if len(MetricStreams) == 0 {
+		fmt.Printf("No Metric Streams for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := MetricStreamFinder(svc, MetricStreams, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func LogGroupFinder(svc *cloudwatchlogs.CloudWatchLogs, LogGroupList []*cloudwatchlogs.LogGroup, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, LogGroup := range LogGroupList {
+		// DescribeLogGroups returns the ARN with a trailing ":*" which the
+		// tagging API does not accept.
+		LogGroupArn := strings.TrimSuffix(aws.StringValue(LogGroup.Arn), ":*")
+		result, err := svc.ListTagsForResource(&cloudwatchlogs.ListTagsForResourceInput{
+			ResourceArn: aws.String(LogGroupArn),
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *LogGroup.LogGroupName, err)
+			continue
+		}
+		Retention := "never expire"
+		if LogGroup.RetentionInDays != nil {
+			Retention = strconv.FormatInt(*LogGroup.RetentionInDays, 10) + " days"
+		}
+		Resources = append(Resources, Resource{
+			Type:      "cloudwatch-loggroup",
+			ID:        *LogGroup.LogGroupName,
+			Arn:       LogGroupArn,
+			Tags:      aws.StringValueMap(result.Tags),
+			CreatedAt: time.UnixMilli(aws.Int64Value(LogGroup.CreationTime)),
+			Attributes: map[string]string{
+				"Retention":   Retention,
+				"StoredBytes": strconv.FormatInt(aws.Int64Value(LogGroup.StoredBytes), 10),
+			},
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func LogGroupInit(PolicyObject *Policy, sess *session.Session) {
+	svc := cloudwatchlogs.New(sess)
+	var LogGroups []*cloudwatchlogs.LogGroup
+	err := svc.DescribeLogGroupsPages(&cloudwatchlogs.DescribeLogGroupsInput{}, func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+		LogGroups = append(LogGroups, page.LogGroups...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Log Groups %v\n", err)
+		return
+	}
+	if len(LogGroups) == 0 {
+		fmt.Printf("No Log Groups for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := LogGroupFinder(svc, LogGroups, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const PolicyUsage = `Usage: tag-police policy <command> [-f policy.yaml] [-t policy.test.yaml] [-e exemptions.yaml]
+
+Commands:
+  show    print the effective policy, with includes and extends resolved
+  schema  print the JSON Schema of policy files
+  test    check the policy against the test cases of a fixture file
+  import-org <tag-policy.json>
+          print an AWS Organizations tag policy as tag-police policies
+  export-org
+          print the policy as an AWS Organizations tag policy`
+
+// PolicyCommand runs the policy subcommands, which work on the policy file
+// alone and make no AWS calls.
+func PolicyCommand(Args []string) {
+	if len(Args) == 0 {
+		fmt.Println(PolicyUsage)
+		os.Exit(2)
+	}
+	Flags := flag.NewFlagSet("policy "+Args[0], flag.ExitOnError)
+	PolicyFile := Flags.String("f", "policy.yaml", "policy file")
+	TestFile := Flags.String("t", "policy.test.yaml", "policy test fixture file")
+	ExemptionFile := Flags.String("e", "exemptions.yaml", "exemptions file")
+	Flags.Parse(Args[1:])
+	switch Args[0] {
+	case "show":
+		PolicyShow(*PolicyFile)
+	case "schema":
+		PolicySchema()
+	case "test":
+		ExemptionList = GetExemptionData(*ExemptionFile)
+		if PolicyTestCommand(*PolicyFile, *TestFile) > 0 {
+			os.Exit(1)
+		}
+	case "import-org":
+		if Flags.NArg() != 1 {
+			fmt.Println(PolicyUsage)
+			os.Exit(2)
+		}
+		PolicyImportOrg(Flags.Arg(0))
+	case "export-org":
+		PolicyExportOrg(*PolicyFile)
+	default:
+		fmt.Println(PolicyUsage)
+		os.Exit(2)
+	}
+}
+
+// PolicyImportOrg prints the tag-police policies of a tag policy. Warnings go
+// to stderr so the output can be redirected to a policy file.
+func PolicyImportOrg(TagPolicyFile string) {
+	Input, err := ioutil.ReadFile(TagPolicyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var Document OrgTagPolicy
+	if err := json.Unmarshal(Input, &Document); err != nil {
+		log.Fatalf("%s: %v", TagPolicyFile, err)
+	}
+	Rules, Warnings := ImportOrgTagPolicy(Document)
+	for _, Warning := range Warnings {
+		log.Println(Warning)
+	}
+	Output, err := yaml.Marshal(&Policy{Policy: Rules})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(Output))
+}
+
+func PolicyExportOrg(PolicyFile string) {
+	Document, Warnings := ExportOrgTagPolicy(GetPolicyData(PolicyFile).Policy)
+	for _, Warning := range Warnings {
+		log.Println(Warning)
+	}
+	Output, err := json.MarshalIndent(Document, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(Output))
+}
+
+func PolicySchema() {
+	Output, err := json.MarshalIndent(GetPolicySchema(), "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(Output))
+}
+
+func PolicyShow(PolicyFile string) {
+	Output, err := yaml.Marshal(GetPolicyData(PolicyFile))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(Output))
+}
+
+// RemediateCommand scans like a plain run, then tags the violations with the
+// policy defaults. It only prints the plan unless -apply is given.
+func RemediateCommand(Args []string) {
+	Flags := flag.NewFlagSet("remediate", flag.ExitOnError)
+	PolicyFile := Flags.String("f", "policy.yaml", "policy file")
+	ExemptionFile := Flags.String("exemptions", "exemptions.yaml", "exemptions file")
+	Apply := Flags.Bool("apply", false, "tag the resources, without it the plan is only printed")
+	Journal := Flags.String("log", "remediation.log", "journal every write is appended to")
+	Flags.Parse(Args)
+
+	VarCheck()
+	PolicyObject := GetPolicyData(*PolicyFile)
+	ExemptionList = GetExemptionData(*ExemptionFile)
+	sess := NewSession()
+	RunPolicy(PolicyObject, sess)
+	Plans := GetRemediationPlans(PolicyObject, Report)
+	PrintRemediationPlans(Plans)
+	if !*Apply {
+		fmt.Println("\nDry run, nothing was tagged. Rerun with -apply to tag the resources.")
+		return
+	}
+	Run := NewRunID()
+	fmt.Printf("\nRun %s, undo with: tag-police rollback -run %s\n", Run, Run)
+	if Failed := ApplyRemediationPlans(sess, Plans, *Journal, Run); Failed > 0 {
+		log.Fatalf("%d resources could not be tagged, see %s", Failed, *Journal)
+	}
+}
+
+// QuarantineCommand scans like a plain run, then plans the quarantine actions
+// of violations past their deadline. Only approved actions are run, and only
+// with -apply.
+func QuarantineCommand(Args []string) {
+	Flags := flag.NewFlagSet("quarantine", flag.ExitOnError)
+	PolicyFile := Flags.String("f", "policy.yaml", "policy file")
+	ExemptionFile := Flags.String("exemptions", "exemptions.yaml", "exemptions file")
+	Apply := Flags.Bool("apply", false, "run the approved actions, without it the plan is only printed")
+	ApprovalFile := Flags.String("approvals", "approvals.yaml", "approvals file")
+	StateFile := Flags.String("state", "quarantine-state.json", "file recording when violations were first seen")
+	Journal := Flags.String("log", "remediation.log", "journal every write is appended to")
+	Flags.Parse(Args)
+
+	VarCheck()
+	PolicyObject := GetPolicyData(*PolicyFile)
+	ExemptionList = GetExemptionData(*ExemptionFile)
+	ApprovalList := GetApprovalData(*ApprovalFile)
+	State := GetQuarantineState(*StateFile)
+	sess := NewSession()
+	RunPolicy(PolicyObject, sess)
+	Plans, NewState := GetQuarantinePlans(PolicyObject, Report, State, ApprovalList, time.Now())
+	if err := NewState.Save(*StateFile); err != nil {
+		log.Fatal(err)
+	}
+	PrintQuarantinePlans(Plans)
+	if !*Apply {
+		fmt.Println("\nDry run, nothing was changed. Rerun with -apply to run the approved actions.")
+		return
+	}
+	Run := NewRunID()
+	fmt.Printf("\nRun %s, undo with: tag-police rollback -run %s\n", Run, Run)
+	if Failed := ApplyQuarantinePlans(sess, Plans, *Journal, Run); Failed > 0 {
+		log.Fatalf("%d quarantine actions failed, see %s", Failed, *Journal)
+	}
+}
+
+// RollbackCommand undoes the writes of a remediate or quarantine run, latest
+// first, from the journal. Like those, it only prints the plan without -apply.
+func RollbackCommand(Args []string) {
+	Flags := flag.NewFlagSet("rollback", flag.ExitOnError)
+	Run := Flags.String("run", "", "ID of the run to roll back, printed by remediate and quarantine")
+	Apply := Flags.Bool("apply", false, "restore the resources, without it the plan is only printed")
+	Journal := Flags.String("log", "remediation.log", "journal every write is appended to")
+	Flags.Parse(Args)
+	if *Run == "" {
+		Flags.Usage()
+		os.Exit(2)
+	}
+
+	Entries, err := GetJournal(*Journal)
+	if err != nil {
+		log.Fatal(err)
+	}
+	RunEntries := GetRunEntries(Entries, *Run)
+	if len(RunEntries) == 0 {
+		log.Fatalf("%s has no writes of run %s left to roll back", *Journal, *Run)
+	}
+	fmt.Printf("Rollback plan for run %s:\n", *Run)
+	PrintRollbackPlan(RunEntries)
+	if !*Apply {
+		fmt.Println("\nDry run, nothing was changed. Rerun with -apply to restore the resources.")
+		return
+	}
+
+	VarCheck()
+	Rollback := NewRunID()
+	fmt.Printf("\nRun %s\n", Rollback)
+	if Failed := ApplyRollback(NewSession(), RunEntries, *Journal, Rollback); Failed > 0 {
+		log.Fatalf("%d writes could not be rolled back, see %s", Failed, *Journal)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LoadPolicyFile returns the policies of a policy file, those of the files it
+// includes first. Include paths are relative to the including file, and a
+// file included more than once is only loaded the first time.
+func LoadPolicyFile(FilePath string, Loaded map[string]bool) ([]PolicyRule, error) {
+	AbsPath, err := filepath.Abs(FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if Loaded[AbsPath] {
+		return nil, nil
+	}
+	Loaded[AbsPath] = true
+	yamlFile, err := ioutil.ReadFile(FilePath)
+	if err != nil {
+		return nil, err
+	}
+	// Unknown fields and repeated mapping keys are errors, so a typo such as
+	// key: instead of keys: doesn't silently disable a check.
+	var data Policy
+	if err := yaml.UnmarshalStrict(yamlFile, &data); err != nil {
+		return nil, fmt.Errorf("%s: %v", FilePath, err)
+	}
+	Line := 1
+	for index := range data.Policy {
+		Line = FindLine(yamlFile, "name", data.Policy[index].Name, Line)
+		data.Policy[index].File, data.Policy[index].Line = FilePath, Line
+	}
+	var Rules []PolicyRule
+	for _, Include := range data.Include {
+		if !filepath.IsAbs(Include) {
+			Include = filepath.Join(filepath.Dir(FilePath), Include)
+		}
+		Files, err := GetIncludeFiles(Include)
+		if err != nil {
+			return nil, fmt.Errorf("%s: include: %v", FilePath, err)
+		}
+		for _, File := range Files {
+			IncludedRules, err := LoadPolicyFile(File, Loaded)
+			if err != nil {
+				return nil, err
+			}
+			Rules = append(Rules, IncludedRules...)
+		}
+	}
+	return append(Rules, data.Policy...), nil
+}
+
+// GetIncludeFiles returns the policy files an include refers to, the file
+// itself or the .yaml and .yml files of a directory in name order.
+func GetIncludeFiles(Path string) ([]string, error) {
+	Info, err := os.Stat(Path)
+	if err != nil {
+		return nil, err
+	}
+	if !Info.IsDir() {
+		return []string{Path}, nil
+	}
+	var Files []string
+	for _, Pattern := range []string{"*.yaml", "*.yml"} {
+		Matches, err := filepath.Glob(filepath.Join(Path, Pattern))
+		if err != nil {
+			return nil, err
+		}
+		Files = append(Files, Matches...)
+	}
+	sort.Strings(Files)
+	return Files, nil
+}
+
+// ResolvePolicyRules merges every policy with the policy it extends and drops
+// abstract policies, which only exist to be extended.
+func ResolvePolicyRules(Rules []PolicyRule) ([]PolicyRule, error) {
+	NamedRules := make(map[string]PolicyRule)
+	for _, Rule := range Rules {
+		if Rule.Name == "" {
+			continue
+		}
+		if _, ok := NamedRules[Rule.Name]; ok {
+			return nil, fmt.Errorf("%s: policy %s is already defined at %s", Rule.Location(), Rule.Name, NamedRules[Rule.Name].Location())
+		}
+		NamedRules[Rule.Name] = Rule
+	}
+	var ResolvedRules []PolicyRule
+	for _, Rule := range Rules {
+		if Rule.Abstract {
+			continue
+		}
+		ResolvedRule, err := ResolvePolicyRule(Rule, NamedRules, nil)
+		if err != nil {
+			return nil, err
+		}
+		ResolvedRules = append(ResolvedRules, ResolvedRule)
+	}
+	return ResolvedRules, nil
+}
+
+func ResolvePolicyRule(Rule PolicyRule, NamedRules map[string]PolicyRule, Chain []string) (PolicyRule, error) {
+	if Rule.Extend == "" {
+		return Rule, nil
+	}
+	Chain = append(Chain, Rule.Name)
+	for _, Name := range Chain {
+		if Name == Rule.Extend {
+			return Rule, fmt.Errorf("%s: policy %s: extend cycle %v", Rule.Location(), Rule.Name, append(Chain, Rule.Extend))
+		}
+	}
+	Parent, ok := NamedRules[Rule.Extend]
+	if !ok {
+		return Rule, fmt.Errorf("%s: policy %s: extends unknown policy %s", Rule.Location(), Rule.Name, Rule.Extend)
+	}
+	Parent, err := ResolvePolicyRule(Parent, NamedRules, Chain)
+	if err != nil {
+		return Rule, err
+	}
+	return MergePolicyRule(Parent, Rule), nil
+}
+
+// MergePolicyRule overrides the parent policy with the options the child
+// sets. Keys and checks are merged: a child key or check replaces the
+// parent's of the same name and new ones are added after the parent's.
+func MergePolicyRule(Parent, Child PolicyRule) PolicyRule {
+	Rule := Child
+	Rule.Extend = ""
+	Rule.Keys = MergePolicyKeys(Parent.Keys, Child.Keys)
+	Rule.Checks = MergeCustomChecks(Parent.Checks, Child.Checks)
+	if len(Child.Resources) == 0 {
+		Rule.Resources = Parent.Resources
+	}
+	if len(Child.InstanceStates) == 0 {
+		Rule.InstanceStates = Parent.InstanceStates
+	}
+	if len(Child.Selector.Include) == 0 && len(Child.Selector.Exclude) == 0 {
+		Rule.Selector = Parent.Selector
+	}
+	if Child.Suppression.Tag == "" {
+		Rule.Suppression.Tag = Parent.Suppression.Tag
+	}
+	if len(Child.Suppression.Disabled) == 0 {
+		Rule.Suppression.Disabled = Parent.Suppression.Disabled
+	}
+	if Child.GracePeriod == "" {
+		Rule.GracePeriod = Parent.GracePeriod
+	}
+	if Child.Severity == "" {
+		Rule.Severity = Parent.Severity
+	}
+	if Child.Mode == "" {
+		Rule.Mode = Parent.Mode
+	}
+	if Child.Inherit == "" {
+		Rule.Inherit = Parent.Inherit
+	}
+	if Child.Caseinsenstive == nil {
+		Rule.Caseinsenstive = Parent.Caseinsenstive
+	}
+	if Child.Quarantine.After == "" && len(Child.Quarantine.Actions) == 0 {
+		Rule.Quarantine = Parent.Quarantine
+	}
+	return Rule
+}
+
+func MergePolicyKeys(ParentKeys, ChildKeys []PolicyKey) []PolicyKey {
+	var Keys []PolicyKey
+	Overridden := make(map[string]bool)
+	for _, ParentKey := range ParentKeys {
+		for _, ChildKey := range ChildKeys {
+			if ChildKey.Key == ParentKey.Key {
+				ParentKey = ChildKey
+				Overridden[ChildKey.Key] = true
+			}
+		}
+		Keys = append(Keys, ParentKey)
+	}
+	for _, ChildKey := range ChildKeys {
+		if !Overridden[ChildKey.Key] {
+			Keys = append(Keys, ChildKey)
+		}
+	}
+	return Keys
+}
+
+func MergeCustomChecks(ParentChecks, ChildChecks []CustomCheck) []CustomCheck {
+	var Checks []CustomCheck
+	Overridden := make(map[string]bool)
+	for _, ParentCheck := range ParentChecks {
+		for _, ChildCheck := range ChildChecks {
+			if ChildCheck.Name == ParentCheck.Name {
+				ParentCheck = ChildCheck
+				Overridden[ChildCheck.Name] = true
+			}
+		}
+		Checks = append(Checks, ParentCheck)
+	}
+	for _, ChildCheck := range ChildChecks {
+		if !Overridden[ChildCheck.Name] {
+			Checks = append(Checks, ChildCheck)
+		}
+	}
+	return Checks
+}
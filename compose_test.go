@@ -0,0 +1,89 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergePolicyRule(t *testing.T) {
+	True, False := true, false
+	Parent := PolicyRule{
+		Name:           "base",
+		Resources:      []string{"ec2", "s3"},
+		Caseinsenstive: &True,
+		Keys:           []PolicyKey{{Key: "Team"}, {Key: "Environment", Values: []string{"dev", "prod"}}},
+		Severity:       SeverityHigh,
+		Mode:           ModeEnforce,
+	}
+	Cases := []struct {
+		Name     string
+		Child    PolicyRule
+		Expected PolicyRule
+	}{
+		{
+			Name:  "inherits what the child doesn't set",
+			Child: PolicyRule{Name: "child", Extend: "base"},
+			Expected: PolicyRule{
+				Name:           "child",
+				Resources:      []string{"ec2", "s3"},
+				Caseinsenstive: &True,
+				Keys:           []PolicyKey{{Key: "Team"}, {Key: "Environment", Values: []string{"dev", "prod"}}},
+				Severity:       SeverityHigh,
+				Mode:           ModeEnforce,
+			},
+		},
+		{
+			Name: "child overrides options and keys",
+			Child: PolicyRule{
+				Name:           "child",
+				Extend:         "base",
+				Resources:      []string{"rds"},
+				Caseinsenstive: &False,
+				Keys:           []PolicyKey{{Key: "Environment", Values: []string{"staging"}}, {Key: "Owner"}},
+				Mode:           ModeAudit,
+			},
+			Expected: PolicyRule{
+				Name:           "child",
+				Resources:      []string{"rds"},
+				Caseinsenstive: &False,
+				Keys:           []PolicyKey{{Key: "Team"}, {Key: "Environment", Values: []string{"staging"}}, {Key: "Owner"}},
+				Severity:       SeverityHigh,
+				Mode:           ModeAudit,
+			},
+		},
+	}
+	for _, Case := range Cases {
+		Rule := MergePolicyRule(Parent, Case.Child)
+		if !reflect.DeepEqual(Rule, Case.Expected) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", Case.Name, Rule, Case.Expected)
+		}
+	}
+}
+
+func TestResolvePolicyRules(t *testing.T) {
+	Cases := []struct {
+		Name  string
+		Rules []PolicyRule
+		Names []string
+		Error bool
+	}{
+		{"abstract left out", []PolicyRule{{Name: "base", Abstract: true}, {Name: "child", Extend: "base"}}, []string{"child"}, false},
+		{"unknown parent", []PolicyRule{{Name: "child", Extend: "missing"}}, nil, true},
+		{"cycle", []PolicyRule{{Name: "a", Extend: "b"}, {Name: "b", Extend: "a"}}, nil, true},
+		{"duplicate name", []PolicyRule{{Name: "a"}, {Name: "a"}}, nil, true},
+	}
+	for _, Case := range Cases {
+		Rules, err := ResolvePolicyRules(Case.Rules)
+		if (err != nil) != Case.Error {
+			t.Errorf("%s: error %v, want error %v", Case.Name, err, Case.Error)
+			continue
+		}
+		var Names []string
+		for _, Rule := range Rules {
+			Names = append(Names, Rule.Name)
+		}
+		if !reflect.DeepEqual(Names, Case.Names) {
+			t.Errorf("%s: got %v, want %v", Case.Name, Names, Case.Names)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// CreateEventPrefixes are the prefixes of the CloudTrail events that create
+// resources, e.g. CreateBucket, RunInstances or AllocateAddress.
+var CreateEventPrefixes = []string{"Create", "Run", "Allocate", "Copy", "Register", "Purchase", "Import"}
+
+// Creator is who created a resource, from the CloudTrail event that created
+// it. Principal is the IAM user or role, SessionName the role session.
+type Creator struct {
+	Principal   string
+	SessionName string
+	EventName   string
+	EventTime   time.Time
+}
+
+func (Creator Creator) String() string {
+	Description := Creator.Principal
+	if Creator.SessionName != "" {
+		Description += " (session " + Creator.SessionName + ")"
+	}
+	return Description + " via " + Creator.EventName + " at " + Creator.EventTime.Format(time.RFC3339)
+}
+
+type CloudTrailResource struct {
+	ARN string `json:"ARN"`
+}
+
+// CloudTrailRecord is the part of a CloudTrail event used for attribution.
+type CloudTrailRecord struct {
+	EventName    string `json:"eventName"`
+	EventTime    string `json:"eventTime"`
+	UserIdentity struct {
+		Type           string `json:"type"`
+		Arn            string `json:"arn"`
+		SessionContext struct {
+			SessionIssuer struct {
+				Arn string `json:"arn"`
+			} `json:"sessionIssuer"`
+		} `json:"sessionContext"`
+	} `json:"userIdentity"`
+	Resources         []CloudTrailResource `json:"resources"`
+	RequestParameters interface{}          `json:"requestParameters"`
+	ResponseElements  interface{}          `json:"responseElements"`
+}
+
+// IgnoredCreateEvents match CreateEventPrefixes but only change existing
+// resources.
+var IgnoredCreateEvents = []string{"CreateTags", "CreateOrUpdateTags"}
+
+// CreatedKeyPrefixes maps the resource an event creates, from its name, to the
+// prefix of the keys naming it in the event, where the two differ.
+var CreatedKeyPrefixes = map[string]string{
+	"address":                   "allocation",
+	"securitygroup":             "group",
+	"reservedinstancesoffering": "reservedinstances",
+}
+
+func IsCreateEvent(EventName string) bool {
+	return GetCreatedKeyPrefix(EventName) != ""
+}
+
+// GetCreatedKeyPrefix returns the lower-cased prefix of the request and
+// response keys that name the resource the event creates, e.g. "volume" for
+// CreateVolume or "instance" for RunInstances, "" for other events.
+func GetCreatedKeyPrefix(EventName string) string {
+	if ContainsString(IgnoredCreateEvents, EventName) {
+		return ""
+	}
+	for _, Prefix := range CreateEventPrefixes {
+		if strings.HasPrefix(EventName, Prefix) {
+			// Lambda's event names carry the API version, e.g. CreateFunction20150331.
+			Noun := strings.ToLower(strings.TrimRight(strings.TrimPrefix(EventName, Prefix), "0123456789"))
+			if KeyPrefix, ok := CreatedKeyPrefixes[Noun]; ok {
+				return KeyPrefix
+			}
+			return strings.TrimSuffix(Noun, "s")
+		}
+	}
+	return ""
+}
+
+// IsCreatedKey reports whether a key of the event names the created resource,
+// either by its prefix, e.g. snapshotId, or a generic id, arn or name of an
+// object named after the resource, e.g. hostedZone.id, or at the top level.
+func IsCreatedKey(Key string, Parent string, KeyPrefix string) bool {
+	Key, Parent = strings.ToLower(Key), strings.ToLower(Parent)
+	if strings.HasPrefix(Key, KeyPrefix) {
+		return true
+	}
+	switch Key {
+	case "id", "arn", "name":
+		return Parent == "" || strings.HasPrefix(Parent, KeyPrefix)
+	}
+	return false
+}
+
+func (Record CloudTrailRecord) Creator() Creator {
+	Creator := Creator{
+		Principal: Record.UserIdentity.Arn,
+		EventName: Record.EventName,
+		EventTime: ParseTime(Record.EventTime),
+	}
+	// An assumed role's ARN ends with the session name, the role is the
+	// session issuer.
+	if Record.UserIdentity.Type == "AssumedRole" {
+		Creator.Principal = Record.UserIdentity.SessionContext.SessionIssuer.Arn
+		Creator.SessionName = Record.UserIdentity.Arn[strings.LastIndex(Record.UserIdentity.Arn, "/")+1:]
+	}
+	return Creator
+}
+
+// GetRecordIds returns the ARNs and IDs of the resources the event created:
+// its resources, and the parameters and response elements that name the
+// created resource, e.g. the instance IDs RunInstances returns or the bucket
+// name CreateBucket was called with. IDs the event only refers to, like the
+// volume of CreateSnapshot, are left out.
+func (Record CloudTrailRecord) GetRecordIds() []string {
+	var IDs []string
+	for _, Resource := range Record.Resources {
+		IDs = append(IDs, Resource.ARN)
+	}
+	KeyPrefix := GetCreatedKeyPrefix(Record.EventName)
+	if KeyPrefix == "" {
+		return IDs
+	}
+	var Walk func(Value interface{}, Key string, Parent string)
+	Walk = func(Value interface{}, Key string, Parent string) {
+		switch Value := Value.(type) {
+		case string:
+			if IsCreatedKey(Key, Parent, KeyPrefix) {
+				IDs = append(IDs, Value)
+			}
+		case []interface{}:
+			for _, Item := range Value {
+				Walk(Item, Key, Parent)
+			}
+		case map[string]interface{}:
+			for Name, Item := range Value {
+				Walk(Item, Name, Key)
+			}
+		}
+	}
+	Walk(Record.RequestParameters, "", "")
+	Walk(Record.ResponseElements, "", "")
+	return IDs
+}
+
+// CreatorIndex maps resource IDs and ARNs to their creator, the earliest
+// create event that mentions them.
+type CreatorIndex map[string]Creator
+
+func (Index CreatorIndex) Add(Record CloudTrailRecord) {
+	if !IsCreateEvent(Record.EventName) {
+		return
+	}
+	Creator := Record.Creator()
+	for _, ID := range Record.GetRecordIds() {
+		if Existing, ok := Index[ID]; !ok || Creator.EventTime.Before(Existing.EventTime) {
+			Index[ID] = Creator
+		}
+	}
+}
+
+// AddLogFile indexes a CloudTrail log file, gzipped or not.
+func (Index CreatorIndex) AddLogFile(Name string, Content []byte) error {
+	if strings.HasSuffix(Name, ".gz") {
+		Reader, err := gzip.NewReader(bytes.NewReader(Content))
+		if err != nil {
+			return err
+		}
+		Content, err = ioutil.ReadAll(Reader)
+		if err != nil {
+			return err
+		}
+	}
+	var LogFile struct {
+		Records []CloudTrailRecord `json:"Records"`
+	}
+	if err := json.Unmarshal(Content, &LogFile); err != nil {
+		return err
+	}
+	for _, Record := range LogFile.Records {
+		Index.Add(Record)
+	}
+	return nil
+}
+
+func IsLogFile(Name string) bool {
+	return strings.HasSuffix(Name, ".json") || strings.HasSuffix(Name, ".json.gz")
+}
+
+// GetArchiveCreatorIndex indexes a CloudTrail log archive, a local directory
+// or an s3://bucket/prefix URL.
+func GetArchiveCreatorIndex(sess *session.Session, Archive string) (CreatorIndex, error) {
+	Index := make(CreatorIndex)
+	if strings.HasPrefix(Archive, "s3://") {
+		Bucket, Prefix, _ := strings.Cut(strings.TrimPrefix(Archive, "s3://"), "/")
+		svc := s3.New(sess)
+		var Keys []string
+		err := svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+			Bucket: aws.String(Bucket),
+			Prefix: aws.String(Prefix),
+		}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, Object := range page.Contents {
+				if IsLogFile(aws.StringValue(Object.Key)) {
+					Keys = append(Keys, aws.StringValue(Object.Key))
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, Key := range Keys {
+			result, err := svc.GetObject(&s3.GetObjectInput{Bucket: aws.String(Bucket), Key: aws.String(Key)})
+			if err != nil {
+				return nil, err
+			}
+			Content, err := ioutil.ReadAll(result.Body)
+			result.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			if err := Index.AddLogFile(Key, Content); err != nil {
+				return nil, fmt.Errorf("%s: %v", Key, err)
+			}
+		}
+		return Index, nil
+	}
+	err := filepath.Walk(Archive, func(Path string, Info os.FileInfo, err error) error {
+		if err != nil || Info.IsDir() || !IsLogFile(Path) {
+			return err
+		}
+		Content, err := ioutil.ReadFile(Path)
+		if err != nil {
+			return err
+		}
+		if err := Index.AddLogFile(Path, Content); err != nil {
+			return fmt.Errorf("%s: %v", Path, err)
+		}
+		return nil
+	})
+	return Index, err
+}
+
+// LookupCreator finds the event that created the resource with LookupEvents,
+// which covers the last 90 days of the region's management events.
+func LookupCreator(svc *cloudtrail.CloudTrail, Resource Resource) (Creator, bool) {
+	Index := make(CreatorIndex)
+	input := &cloudtrail.LookupEventsInput{
+		LookupAttributes: []*cloudtrail.LookupAttribute{{
+			AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+			AttributeValue: aws.String(Resource.ID),
+		}},
+	}
+	err := svc.LookupEventsPages(input, func(page *cloudtrail.LookupEventsOutput, lastPage bool) bool {
+		for _, Event := range page.Events {
+			if !IsCreateEvent(aws.StringValue(Event.EventName)) {
+				continue
+			}
+			var Record CloudTrailRecord
+			if err := json.Unmarshal([]byte(aws.StringValue(Event.CloudTrailEvent)), &Record); err != nil {
+				continue
+			}
+			Index.Add(Record)
+		}
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to look up events for %s %v\n", Resource.ID, err)
+	}
+	return Index.Find(Resource)
+}
+
+func (Index CreatorIndex) Find(Resource Resource) (Creator, bool) {
+	if Creator, ok := Index[Resource.ID]; ok {
+		return Creator, true
+	}
+	if Resource.Arn != "" {
+		if Creator, ok := Index[Resource.Arn]; ok {
+			return Creator, true
+		}
+	}
+	return Creator{}, false
+}
+
+// AttributeCreators attaches the creator to every violation in the report.
+// Source is "lookup" to use LookupEvents, or a CloudTrail log archive.
+func AttributeCreators(sess *session.Session, Source string) {
+	var Index CreatorIndex
+	if Source != "lookup" {
+		var err error
+		Index, err = GetArchiveCreatorIndex(sess, Source)
+		if err != nil {
+			fmt.Printf("Unable to load CloudTrail archive %s %v\n", Source, err)
+			return
+		}
+	}
+	svc := cloudtrail.New(sess)
+	// A resource violating several policies is looked up once.
+	Found := make(map[string]*Creator)
+	for index := range Report {
+		Finding := &Report[index]
+		if Finding.Status != StatusViolation {
+			continue
+		}
+		Key := Finding.Resource.Region + "/" + Finding.Resource.Type + "/" + Finding.Resource.ID
+		Attributed, ok := Found[Key]
+		if !ok {
+			var Result Creator
+			var found bool
+			if Index != nil {
+				Result, found = Index.Find(Finding.Resource)
+			} else {
+				Result, found = LookupCreator(svc, Finding.Resource)
+			}
+			if found {
+				Attributed = &Result
+			}
+			Found[Key] = Attributed
+		}
+		Finding.Creator = Attributed
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGetRecordIds(t *testing.T) {
+	Cases := []struct {
+		Record string
+		IDs    []string
+	}{
+		{`{"eventName": "RunInstances", "requestParameters": {"instancesSet": {"items": [{"imageId": "ami-1"}]}, "subnetId": "subnet-1"},
+			"responseElements": {"instancesSet": {"items": [{"instanceId": "i-1", "imageId": "ami-1", "subnetId": "subnet-1"}]}}}`, []string{"i-1"}},
+		{`{"eventName": "CreateSnapshot", "requestParameters": {"volumeId": "vol-1"}, "responseElements": {"snapshotId": "snap-1", "volumeId": "vol-1"}}`, []string{"snap-1"}},
+		{`{"eventName": "CreateBucket", "requestParameters": {"bucketName": "logs", "Host": "logs.s3.amazonaws.com"}}`, []string{"logs"}},
+		{`{"eventName": "CreateTags", "requestParameters": {"resourcesSet": {"items": [{"resourceId": "vol-1"}]}}}`, nil},
+		{`{"eventName": "AllocateAddress", "responseElements": {"allocationId": "eipalloc-1", "publicIp": "192.0.2.1"}}`, []string{"eipalloc-1"}},
+		{`{"eventName": "CreateNetworkInterface", "requestParameters": {"subnetId": "subnet-1"},
+			"responseElements": {"networkInterface": {"networkInterfaceId": "eni-1", "subnetId": "subnet-1", "vpcId": "vpc-1"}}}`, []string{"eni-1"}},
+		{`{"eventName": "CreateHostedZone", "requestParameters": {"name": "example.com."}, "responseElements": {"hostedZone": {"id": "/hostedzone/Z1"}}}`, []string{"/hostedzone/Z1", "example.com."}},
+		{`{"eventName": "CreateFunction20150331", "requestParameters": {"functionName": "f", "role": "arn:aws:iam::1:role/r"}, "responseElements": {"functionArn": "arn:aws:lambda:eu-west-1:1:function:f"}}`,
+			[]string{"arn:aws:lambda:eu-west-1:1:function:f", "f"}},
+	}
+	for _, Case := range Cases {
+		var Record CloudTrailRecord
+		if err := json.Unmarshal([]byte(Case.Record), &Record); err != nil {
+			t.Fatal(err)
+		}
+		IDs := Record.GetRecordIds()
+		sort.Strings(IDs)
+		if !reflect.DeepEqual(IDs, Case.IDs) {
+			t.Errorf("%s: got %v, want %v", Record.EventName, IDs, Case.IDs)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func GetDynamoDBTags(svc *dynamodb.DynamoDB, TableArn *string) (map[string]string, error) {
+	// ListTagsOfResource has no paginator in the SDK, follow NextToken by hand.
+	Tags := make(map[string]string)
+	input := &dynamodb.ListTagsOfResourceInput{
+		ResourceArn: TableArn,
+	}
+	for {
+		result, err := svc.ListTagsOfResource(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, Tag := range result.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+	return Tags, nil
+}
+
+func DynamoDBFinder(svc *dynamodb.DynamoDB, TableNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, TableName := range TableNames {
+		result, err := svc.DescribeTable(&dynamodb.DescribeTableInput{
+			TableName: TableName,
+		})
+		if err != nil {
+			fmt.Printf("Unable to describe DynamoDB Table %s %v\n", *TableName, err)
+			continue
+		}
+		Tags, err := GetDynamoDBTags(svc, result.Table.TableArn)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *TableName, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type:      "dynamodb",
+			ID:        *TableName,
+			Arn:       aws.StringValue(result.Table.TableArn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(result.Table.CreationDateTime),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func DynamoDBInit(PolicyObject *Policy, sess *session.Session) {
+	svc := dynamodb.New(sess)
+	var TableNames []*string
+	err := svc.ListTablesPages(&dynamodb.ListTablesInput{}, func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
+		TableNames = append(TableNames, page.TableNames...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load DynamoDB Tables %v\n", err)
+		return
+	}
+	if len(TableNames) == 0 {
+		fmt.Printf("No DynamoDB Tables for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := DynamoDBFinder(svc, TableNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func EC2VolumeFinder(svc *ec2.EC2, VolumeList []*ec2.Volume, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Volume := range VolumeList {
+		Resource := Resource{
+			Type:      "ec2-volume",
+			ID:        *Volume.VolumeId,
+			Tags:      GetEc2Tags(Volume.Tags),
+			CreatedAt: aws.TimeValue(Volume.CreateTime),
+			Attributes: map[string]string{
+				"State":      aws.StringValue(Volume.State),
+				"VolumeType": aws.StringValue(Volume.VolumeType),
+			},
+		}
+		for _, Attachment := range Volume.Attachments {
+			Resource.Parents = append(Resource.Parents, ParentResource{Type: "ec2", ID: aws.StringValue(Attachment.InstanceId)})
+		}
+		Resources = append(Resources, Resource)
+	}
+	ResolveEC2Parents(svc, Resources, Rule)
+	return ResourceFinder(Resources, Rule)
+}
+
+func EC2VolumeInit(PolicyObject *Policy, sess *session.Session) {
+	svc := ec2.New(sess)
+	var Volumes []*ec2.Volume
+	err := svc.DescribeVolumesPages(&ec2.DescribeVolumesInput{}, func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
+		Volumes = append(Volumes, page.Volumes...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Volumes %v\n", err)
+		return
+	}
+	if len(Volumes) == 0 {
+		fmt.Printf("No Volumes for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := EC2VolumeFinder(svc, Volumes, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func NetworkInterfaceFinder(svc *ec2.EC2, NetworkInterfaceList []*ec2.NetworkInterface, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, NetworkInterface := range NetworkInterfaceList {
+		Resource := Resource{
+			Type: "ec2-networkinterface",
+			ID:   *NetworkInterface.NetworkInterfaceId,
+			Tags: GetEc2Tags(NetworkInterface.TagSet),
+			Attributes: map[string]string{
+				"InterfaceType": aws.StringValue(NetworkInterface.InterfaceType),
+				"VpcId":         aws.StringValue(NetworkInterface.VpcId),
+				"SubnetId":      aws.StringValue(NetworkInterface.SubnetId),
+			},
+		}
+		if NetworkInterface.Attachment != nil && NetworkInterface.Attachment.InstanceId != nil {
+			Resource.Parents = append(Resource.Parents, ParentResource{Type: "ec2", ID: *NetworkInterface.Attachment.InstanceId})
+		}
+		Resources = append(Resources, Resource)
+	}
+	ResolveEC2Parents(svc, Resources, Rule)
+	return ResourceFinder(Resources, Rule)
+}
+
+func NetworkInterfaceInit(PolicyObject *Policy, sess *session.Session) {
+	svc := ec2.New(sess)
+	var NetworkInterfaces []*ec2.NetworkInterface
+	err := svc.DescribeNetworkInterfacesPages(&ec2.DescribeNetworkInterfacesInput{}, func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+		NetworkInterfaces = append(NetworkInterfaces, page.NetworkInterfaces...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Network Interfaces %v\n", err)
+		return
+	}
+	if len(NetworkInterfaces) == 0 {
+		fmt.Printf("No Network Interfaces for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := NetworkInterfaceFinder(svc, NetworkInterfaces, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
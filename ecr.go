@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+func ECRRepositoryFinder(svc *ecr.ECR, RepositoryList []*ecr.Repository, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Repository := range RepositoryList {
+		result, err := svc.ListTagsForResource(&ecr.ListTagsForResourceInput{
+			ResourceArn: Repository.RepositoryArn,
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Repository.RepositoryName, err)
+			continue
+		}
+		Tags := make(map[string]string)
+		for _, Tag := range result.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "ecr-repository",
+			ID:        *Repository.RepositoryName,
+			Arn:       *Repository.RepositoryArn,
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Repository.CreatedAt),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func ECRRepositoryInit(PolicyObject *Policy, sess *session.Session) {
+	svc := ecr.New(sess)
+	var Repositories []*ecr.Repository
+	err := svc.DescribeRepositoriesPages(&ecr.DescribeRepositoriesInput{}, func(page *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
+		Repositories = append(Repositories, page.Repositories...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load ECR Repositories %v\n", err)
+		return
+	}
+	if len(Repositories) == 0 {
+		fmt.Printf("No ECR Repositories for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := ECRRepositoryFinder(svc, Repositories, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// DescribeClusters accepts up to 100 clusters and DescribeServices up to 10
+// services per call.
+const (
+	ECSDescribeClustersLimit = 100
+	ECSDescribeServicesLimit = 10
+)
+
+func GetECSTags(TagList []*ecs.Tag) map[string]string {
+	Tags := make(map[string]string)
+	for _, Tag := range TagList {
+		Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+	}
+	return Tags
+}
+
+func ListECSClusters(svc *ecs.ECS) []*string {
+	var ClusterArns []*string
+	err := svc.ListClustersPages(&ecs.ListClustersInput{}, func(page *ecs.ListClustersOutput, lastPage bool) bool {
+		ClusterArns = append(ClusterArns, page.ClusterArns...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load ECS Clusters %v\n", err)
+	}
+	return ClusterArns
+}
+
+func ECSClusterFinder(svc *ecs.ECS, ClusterArns []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for start := 0; start < len(ClusterArns); start += ECSDescribeClustersLimit {
+		end := start + ECSDescribeClustersLimit
+		if end > len(ClusterArns) {
+			end = len(ClusterArns)
+		}
+		result, err := svc.DescribeClusters(&ecs.DescribeClustersInput{
+			Clusters: ClusterArns[start:end],
+			Include:  aws.StringSlice([]string{ecs.ClusterFieldTags}),
+		})
+		if err != nil {
+			fmt.Printf("Unable to describe ECS Clusters %v\n", err)
+			continue
+		}
+		for _, Cluster := range result.Clusters {
+			Resources = append(Resources, Resource{
+				Type: "ecs-cluster",
+				ID:   aws.StringValue(Cluster.ClusterName),
+				Arn:  aws.StringValue(Cluster.ClusterArn),
+				Tags: GetECSTags(Cluster.Tags),
+			})
+		}
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func ECSClusterInit(PolicyObject *Policy, sess *session.Session) {
+	svc := ecs.New(sess)
+	ClusterArns := ListECSClusters(svc)
+	if len(ClusterArns) == 0 {
+		fmt.Printf("No ECS Clusters for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := ECSClusterFinder(svc, ClusterArns, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func ECSServiceFinder(svc *ecs.ECS, ClusterArns []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, ClusterArn := range ClusterArns {
+		var ServiceArns []*string
+		input := &ecs.ListServicesInput{
+			Cluster: ClusterArn,
+		}
+		err := svc.ListServicesPages(input, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+			ServiceArns = append(ServiceArns, page.ServiceArns...)
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load ECS Services for %s %v\n", *ClusterArn, err)
+			continue
+		}
+		for start := 0; start < len(ServiceArns); start += ECSDescribeServicesLimit {
+			end := start + ECSDescribeServicesLimit
+			if end > len(ServiceArns) {
+				end = len(ServiceArns)
+			}
+			result, err := svc.DescribeServices(&ecs.DescribeServicesInput{
+				Cluster:  ClusterArn,
+				Services: ServiceArns[start:end],
+				Include:  aws.StringSlice([]string{ecs.ServiceFieldTags}),
+			})
+			if err != nil {
+				fmt.Printf("Unable to describe ECS Services for %s %v\n", *ClusterArn, err)
+				continue
+			}
+			for _, Service := range result.Services {
+				Resources = append(Resources, Resource{
+					Type:      "ecs-service",
+					ID:        aws.StringValue(Service.ServiceName),
+					Arn:       aws.StringValue(Service.ServiceArn),
+					Tags:      GetECSTags(Service.Tags),
+					CreatedAt: aws.TimeValue(Service.CreatedAt),
+				})
+			}
+		}
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func ECSServiceInit(PolicyObject *Policy, sess *session.Session) {
+	svc := ecs.New(sess)
+	ClusterArns := ListECSClusters(svc)
+	if len(ClusterArns) == 0 {
+		fmt.Printf("No ECS Clusters for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := ECSServiceFinder(svc, ClusterArns, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func ECSTaskDefinitionFinder(svc *ecs.ECS, TaskDefinitionArns []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, TaskDefinitionArn := range TaskDefinitionArns {
+		result, err := svc.ListTagsForResource(&ecs.ListTagsForResourceInput{
+			ResourceArn: TaskDefinitionArn,
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *TaskDefinitionArn, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "ecs-taskdefinition",
+			ID:   *TaskDefinitionArn,
+			Arn:  *TaskDefinitionArn,
+			Tags: GetECSTags(result.Tags),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func ECSTaskDefinitionInit(PolicyObject *Policy, sess *session.Session) {
+	svc := ecs.New(sess)
+	var TaskDefinitionArns []*string
+	input := &ecs.ListTaskDefinitionsInput{
+		Status: aws.String(ecs.TaskDefinitionStatusActive),
+	}
+	err := svc.ListTaskDefinitionsPages(input, func(page *ecs.ListTaskDefinitionsOutput, lastPage bool) bool {
+		TaskDefinitionArns = append(TaskDefinitionArns, page.TaskDefinitionArns...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load ECS Task Definitions %v\n", err)
+		return
+	}
+	if len(TaskDefinitionArns) == 0 {
+		fmt.Printf("No ECS Task Definitions for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := ECSTaskDefinitionFinder(svc, TaskDefinitionArns, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
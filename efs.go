@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/efs"
+)
+
+func EFSFinder(svc *efs.EFS, FileSystemList []*efs.FileSystemDescription, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, FileSystem := range FileSystemList {
+		Tags := make(map[string]string)
+		input := &efs.ListTagsForResourceInput{
+			ResourceId: FileSystem.FileSystemId,
+		}
+		err := svc.ListTagsForResourcePages(input, func(page *efs.ListTagsForResourceOutput, lastPage bool) bool {
+			for _, Tag := range page.Tags {
+				Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+			}
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *FileSystem.FileSystemId, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type:      "efs",
+			ID:        *FileSystem.FileSystemId,
+			Arn:       aws.StringValue(FileSystem.FileSystemArn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(FileSystem.CreationTime),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func EFSInit(PolicyObject *Policy, sess *session.Session) {
+	svc := efs.New(sess)
+	var FileSystems []*efs.FileSystemDescription
+	err := svc.DescribeFileSystemsPages(&efs.DescribeFileSystemsInput{}, func(page *efs.DescribeFileSystemsOutput, lastPage bool) bool {
+		FileSystems = append(FileSystems, page.FileSystems...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load EFS File Systems %v\n", err)
+		return
+	}
+	if len(FileSystems) == 0 {
+		fmt.Printf("No EFS File Systems for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := EFSFinder(svc, FileSystems, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
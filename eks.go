@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+func ListEKSClusters(svc *eks.EKS) []*string {
+	var ClusterNames []*string
+	err := svc.ListClustersPages(&eks.ListClustersInput{}, func(page *eks.ListClustersOutput, lastPage bool) bool {
+		ClusterNames = append(ClusterNames, page.Clusters...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load EKS Clusters %v\n", err)
+	}
+	return ClusterNames
+}
+
+func EKSClusterFinder(svc *eks.EKS, ClusterNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, ClusterName := range ClusterNames {
+		result, err := svc.DescribeCluster(&eks.DescribeClusterInput{
+			Name: ClusterName,
+		})
+		if err != nil {
+			fmt.Printf("Unable to describe EKS Cluster %s %v\n", *ClusterName, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type:      "eks-cluster",
+			ID:        *ClusterName,
+			Arn:       aws.StringValue(result.Cluster.Arn),
+			Tags:      aws.StringValueMap(result.Cluster.Tags),
+			CreatedAt: aws.TimeValue(result.Cluster.CreatedAt),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func EKSClusterInit(PolicyObject *Policy, sess *session.Session) {
+	svc := eks.New(sess)
+	ClusterNames := ListEKSClusters(svc)
+	if len(ClusterNames) == 0 {
+		fmt.Printf("No EKS Clusters for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := EKSClusterFinder(svc, ClusterNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func EKSNodegroupFinder(svc *eks.EKS, ClusterNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, ClusterName := range ClusterNames {
+		var NodegroupNames []*string
+		input := &eks.ListNodegroupsInput{
+			ClusterName: ClusterName,
+		}
+		err := svc.ListNodegroupsPages(input, func(page *eks.ListNodegroupsOutput, lastPage bool) bool {
+			NodegroupNames = append(NodegroupNames, page.Nodegroups...)
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load Nodegroups for %s %v\n", *ClusterName, err)
+			continue
+		}
+		for _, NodegroupName := range NodegroupNames {
+			result, err := svc.DescribeNodegroup(&eks.DescribeNodegroupInput{
+				ClusterName:   ClusterName,
+				NodegroupName: NodegroupName,
+			})
+			if err != nil {
+				fmt.Printf("Unable to describe Nodegroup %s %v\n", *NodegroupName, err)
+				continue
+			}
+			Resources = append(Resources, Resource{
+				Type:      "eks-nodegroup",
+				ID:        *ClusterName + "/" + *NodegroupName,
+				Arn:       aws.StringValue(result.Nodegroup.NodegroupArn),
+				Tags:      aws.StringValueMap(result.Nodegroup.Tags),
+				CreatedAt: aws.TimeValue(result.Nodegroup.CreatedAt),
+			})
+		}
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func EKSNodegroupInit(PolicyObject *Policy, sess *session.Session) {
+	svc := eks.New(sess)
+	ClusterNames := ListEKSClusters(svc)
+	if len(ClusterNames) == 0 {
+		fmt.Printf("No EKS Clusters for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := EKSNodegroupFinder(svc, ClusterNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func EKSFargateProfileFinder(svc *eks.EKS, ClusterNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, ClusterName := range ClusterNames {
+		var ProfileNames []*string
+		input := &eks.ListFargateProfilesInput{
+			ClusterName: ClusterName,
+		}
+		err := svc.ListFargateProfilesPages(input, func(page *eks.ListFargateProfilesOutput, lastPage bool) bool {
+			ProfileNames = append(ProfileNames, page.FargateProfileNames...)
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load Fargate Profiles for %s %v\n", *ClusterName, err)
+			continue
+		}
+		for _, ProfileName := range ProfileNames {
+			result, err := svc.DescribeFargateProfile(&eks.DescribeFargateProfileInput{
+				ClusterName:        ClusterName,
+				FargateProfileName: ProfileName,
+			})
+			if err != nil {
+				fmt.Printf("Unable to describe Fargate Profile %s %v\n", *ProfileName, err)
+				continue
+			}
+			Resources = append(Resources, Resource{
+				Type:      "eks-fargateprofile",
+				ID:        *ClusterName + "/" + *ProfileName,
+				Arn:       aws.StringValue(result.FargateProfile.FargateProfileArn),
+				Tags:      aws.StringValueMap(result.FargateProfile.Tags),
+				CreatedAt: aws.TimeValue(result.FargateProfile.CreatedAt),
+			})
+		}
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func EKSFargateProfileInit(PolicyObject *Policy, sess *session.Session) {
+	svc := eks.New(sess)
+	ClusterNames := ListEKSClusters(svc)
+	if len(ClusterNames) == 0 {
+		fmt.Printf("No EKS Clusters for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := EKSFargateProfileFinder(svc, ClusterNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+func ElastiCacheFinder(svc *elasticache.ElastiCache, CacheClusterList []*elasticache.CacheCluster, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, CacheCluster := range CacheClusterList {
+		result, err := svc.ListTagsForResource(&elasticache.ListTagsForResourceInput{
+			ResourceName: CacheCluster.ARN,
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *CacheCluster.CacheClusterId, err)
+			continue
+		}
+		Tags := make(map[string]string)
+		for _, Tag := range result.TagList {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "elasticache-cluster",
+			ID:        *CacheCluster.CacheClusterId,
+			Arn:       aws.StringValue(CacheCluster.ARN),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(CacheCluster.CacheClusterCreateTime),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func ElastiCacheInit(PolicyObject *Policy, sess *session.Session) {
+	svc := elasticache.New(sess)
+	var CacheClusters []*elasticache.CacheCluster
+	err := svc.DescribeCacheClustersPages(&elasticache.DescribeCacheClustersInput{}, func(page *elasticache.DescribeCacheClustersOutput, lastPage bool) bool {
+		CacheClusters = append(CacheClusters, page.CacheClusters...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load ElastiCache Clusters %v\n", err)
+		return
+	}
+	if len(CacheClusters) == 0 {
+		fmt.Printf("No ElastiCache Clusters for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := ElastiCacheFinder(svc, CacheClusters, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+)
+
+func GetEventBridgeTags(svc *eventbridge.EventBridge, ResourceArn *string) (map[string]string, error) {
+	result, err := svc.ListTagsForResource(&eventbridge.ListTagsForResourceInput{
+		ResourceARN: ResourceArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	Tags := make(map[string]string)
+	for _, Tag := range result.Tags {
+		Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+	}
+	return Tags, nil
+}
+
+func ListEventBuses(svc *eventbridge.EventBridge) []*eventbridge.EventBus {
+	// ListEventBuses has no paginator in the SDK, follow NextToken by hand.
+	var EventBuses []*eventbridge.EventBus
+	input := &eventbridge.ListEventBusesInput{}
+	for {
+		result, err := svc.ListEventBuses(input)
+		if err != nil {
+			fmt.Printf("Unable to load Event Buses %v\n", err)
+			break
+		}
+		EventBuses = append(EventBuses, result.EventBuses...)
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+	return EventBuses
+}
+
+func EventBusFinder(svc *eventbridge.EventBridge, EventBusList []*eventbridge.EventBus, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, EventBus := range EventBusList {
+		Tags, err := GetEventBridgeTags(svc, EventBus.Arn)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *EventBus.Name, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "eventbridge-bus",
+			ID:   *EventBus.Name,
+			Arn:  aws.StringValue(EventBus.Arn),
+			Tags: Tags,
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func EventBusInit(PolicyObject *Policy, sess *session.Session) {
+	svc := eventbridge.New(sess)
+	EventBuses := ListEventBuses(svc)
+	if len(EventBuses) == 0 {
+		fmt.Printf("No Event Buses for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := EventBusFinder(svc, EventBuses, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func EventRuleFinder(svc *eventbridge.EventBridge, RuleList []*eventbridge.Rule, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Rule := range RuleList {
+		Tags, err := GetEventBridgeTags(svc, Rule.Arn)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Rule.Name, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "eventbridge-rule",
+			ID:   aws.StringValue(Rule.EventBusName) + "/" + *Rule.Name,
+			Arn:  aws.StringValue(Rule.Arn),
+			Tags: Tags,
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func EventRuleInit(PolicyObject *Policy, sess *session.Session) {
+	svc := eventbridge.New(sess)
+	var Rules []*eventbridge.Rule
+	for _, EventBus := range ListEventBuses(svc) {
+		input := &eventbridge.ListRulesInput{
+			EventBusName: EventBus.Name,
+		}
+		for {
+			result, err := svc.ListRules(input)
+			if err != nil {
+				fmt.Printf("Unable to load Rules for %s %v\n", *EventBus.Name, err)
+				break
+			}
+			for _, Rule := range result.Rules {
+				// Rules managed by another AWS service belong to that service.
+				if Rule.ManagedBy != nil {
+					continue
+				}
+				Rules = append(Rules, Rule)
+			}
+			if result.NextToken == nil {
+				break
+			}
+			input.NextToken = result.NextToken
+		}
+	}
+	if len(Rules) == 0 {
+		fmt.Printf("No Event Rules for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := EventRuleFinder(svc, Rules, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ExemptionDateLayout is the layout of an exemption's expires date.
+const ExemptionDateLayout = "2006-01-02"
+
+// Exemption excuses resources from a policy until it expires. Resource is
+// matched against the resource's ARN and ID and accepts * wildcards. An empty
+// Policy applies the exemption to every policy.
+//
+//	exemptions:
+//	- policy: global
+//	  resource: "arn:aws:s3:::vendor-*"
+//	  reason: "Buckets managed by the vendor's deployment"
+//	  owner: "platform-team"
+//	  expires: 2026-12-31
+type Exemption struct {
+	Policy   string `yaml:"policy"`
+	Resource string `yaml:"resource"`
+	Reason   string `yaml:"reason"`
+	Owner    string `yaml:"owner"`
+	Expires  string `yaml:"expires"`
+}
+
+type Exemptions struct {
+	Exemptions []Exemption `yaml:"exemptions"`
+}
+
+var ExemptionList []Exemption
+
+func GetExemptionData(filePath string) []Exemption {
+	// The exemptions file is optional.
+	yamlFile, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Unknown fields are errors like in policies, a typo such as expire:
+	// would otherwise leave the exemption without an expiry.
+	var data Exemptions
+	if err := yaml.UnmarshalStrict(yamlFile, &data); err != nil {
+		log.Fatalf("%s: %v", filePath, err)
+	}
+	Line := 1
+	for index, Exemption := range data.Exemptions {
+		Line = FindLine(yamlFile, "resource", Exemption.Resource, Line)
+		if err := Exemption.Validate(); err != nil {
+			log.Fatalf("%s:%d: exemption %d: %v", filePath, Line, index+1, err)
+		}
+	}
+	return data.Exemptions
+}
+
+func (Exemption Exemption) Validate() error {
+	if Exemption.Resource == "" {
+		return fmt.Errorf("resource is required")
+	}
+	if Exemption.Reason == "" {
+		return fmt.Errorf("reason is required for %s", Exemption.Resource)
+	}
+	if Exemption.Owner == "" {
+		return fmt.Errorf("owner is required for %s", Exemption.Resource)
+	}
+	if _, err := time.Parse(ExemptionDateLayout, Exemption.Expires); err != nil {
+		return fmt.Errorf("expires must be a %s date for %s", ExemptionDateLayout, Exemption.Resource)
+	}
+	return nil
+}
+
+func (Exemption Exemption) Matches(PolicyName string, Resource Resource) bool {
+	if Exemption.Policy != "" && Exemption.Policy != PolicyName {
+		return false
+	}
+	return MatchPattern(Exemption.Resource, Resource.ID) ||
+		(Resource.Arn != "" && MatchPattern(Exemption.Resource, Resource.Arn))
+}
+
+// Expired reports whether the exemption no longer applies. An exemption is
+// valid through the whole of its expiry day.
+func (Exemption Exemption) Expired(Now time.Time) bool {
+	return DateExpired(Exemption.Expires, Now)
+}
+
+// DateExpired reports whether Now is past the YYYY-MM-DD date Date. An empty
+// date never expires.
+func DateExpired(Date string, Now time.Time) bool {
+	if Date == "" {
+		return false
+	}
+	Expires, _ := time.Parse(ExemptionDateLayout, Date)
+	return !Now.Before(Expires.AddDate(0, 0, 1))
+}
+
+func GetExemption(PolicyName string, Resource Resource, Now time.Time) (Exemption, bool) {
+	// An active exemption wins over an expired one for the same resource.
+	var Found Exemption
+	Matched := false
+	for _, Exemption := range ExemptionList {
+		if !Exemption.Matches(PolicyName, Resource) {
+			continue
+		}
+		if !Exemption.Expired(Now) {
+			return Exemption, true
+		}
+		Found, Matched = Exemption, true
+	}
+	return Found, Matched
+}
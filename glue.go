@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// Glue only returns names, GetTags needs the ARN which is built from the
+// account, e.g. arn:aws:glue:eu-west-1:123456789012:job/nightly-etl.
+
+func GlueFinder(svc *glue.Glue, Account *AccountIdentity, ResourceName, ArnPrefix string, Names []string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Name := range Names {
+		ResourceArn := Account.Arn("glue", *svc.Config.Region, ArnPrefix+"/"+Name)
+		result, err := svc.GetTags(&glue.GetTagsInput{
+			ResourceArn: aws.String(ResourceArn),
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", Name, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: ResourceName,
+			ID:   Name,
+			Arn:  ResourceArn,
+			Tags: aws.StringValueMap(result.Tags),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func GlueInit(PolicyObject *Policy, sess *session.Session, ResourceName, ArnPrefix string, List func(svc *glue.Glue) ([]string, error)) {
+	svc := glue.New(sess)
+	Account, err := GetAccountIdentity(sess)
+	if err != nil {
+		fmt.Printf("Unable to load account identity %v\n", err)
+		return
+	}
+	Names, err := List(svc)
+	if err != nil {
+		fmt.Printf("Unable to load %s %v\n", ResourceName, err)
+		return
+	}
+	if len(Names) == 0 {
+		fmt.Printf("No %s for %s region\n", ResourceName, *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := GlueFinder(svc, Account, ResourceName, ArnPrefix, Names, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func GlueJobInit(PolicyObject *Policy, sess *session.Session) {
+	GlueInit(PolicyObject, sess, "glue-job", "job", func(svc *glue.Glue) ([]string, error) {
+		var Names []string
+		err := svc.GetJobsPages(&glue.GetJobsInput{}, func(page *glue.GetJobsOutput, lastPage bool) bool {
+			for _, Job := range page.Jobs {
+				Names = append(Names, aws.StringValue(Job.Name))
+			}
+			return true
+		})
+		return Names, err
+	})
+}
+
+func GlueTriggerInit(PolicyObject *Policy, sess *session.Session) {
+	GlueInit(PolicyObject, sess, "glue-trigger", "trigger", func(svc *glue.Glue) ([]string, error) {
+		var Names []string
+		err := svc.GetTriggersPages(&glue.GetTriggersInput{}, func(page *glue.GetTriggersOutput, lastPage bool) bool {
+			for _, Trigger := range page.Triggers {
+				Names = append(Names, aws.StringValue(Trigger.Name))
+			}
+			return true
+		})
+		return Names, err
+	})
+}
+
+func GlueCrawlerInit(PolicyObject *Policy, sess *session.Session) {
+	GlueInit(PolicyObject, sess, "glue-crawler", "crawler", func(svc *glue.Glue) ([]string, error) {
+		var Names []string
+		err := svc.GetCrawlersPages(&glue.GetCrawlersInput{}, func(page *glue.GetCrawlersOutput, lastPage bool) bool {
+			for _, Crawler := range page.Crawlers {
+				Names = append(Names, aws.StringValue(Crawler.Name))
+			}
+			return true
+		})
+		return Names, err
+	})
+}
+
+func GlueDatabaseInit(PolicyObject *Policy, sess *session.Session) {
+	GlueInit(PolicyObject, sess, "glue-database", "database", func(svc *glue.Glue) ([]string, error) {
+		var Names []string
+		err := svc.GetDatabasesPages(&glue.GetDatabasesInput{}, func(page *glue.GetDatabasesOutput, lastPage bool) bool {
+			for _, Database := range page.DatabaseList {
+				Names = append(Names, aws.StringValue(Database.Name))
+			}
+			return true
+		})
+		return Names, err
+	})
+}
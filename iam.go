@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// IAM is a global service: roles, users and policies are listed once per
+// account through GetGlobalResources, whichever region the session uses.
+
+func GetIAMTags(TagList []*iam.Tag, Tags map[string]string) {
+	for _, Tag := range TagList {
+		Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+	}
+}
+
+func ListIAMRoles(svc *iam.IAM) []Resource {
+	var Roles []*iam.Role
+	err := svc.ListRolesPages(&iam.ListRolesInput{}, func(page *iam.ListRolesOutput, lastPage bool) bool {
+		Roles = append(Roles, page.Roles...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load IAM Roles %v\n", err)
+		return nil
+	}
+	var Resources []Resource
+Roles:
+	for _, Role := range Roles {
+		// Service-linked roles are owned by the AWS service that created them.
+		if strings.HasPrefix(aws.StringValue(Role.Path), "/aws-service-role/") {
+			continue
+		}
+		Tags := make(map[string]string)
+		input := &iam.ListRoleTagsInput{
+			RoleName: Role.RoleName,
+		}
+		for {
+			result, err := svc.ListRoleTags(input)
+			if err != nil {
+				fmt.Printf("Unable to load tags for %s %v\n", *Role.RoleName, err)
+				continue Roles
+			}
+			GetIAMTags(result.Tags, Tags)
+			if !aws.BoolValue(result.IsTruncated) {
+				break
+			}
+			input.Marker = result.Marker
+		}
+		Resources = append(Resources, Resource{
+			Type:      "iam-role",
+			ID:        *Role.RoleName,
+			Arn:       aws.StringValue(Role.Arn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Role.CreateDate),
+		})
+	}
+	return Resources
+}
+
+func ListIAMUsers(svc *iam.IAM) []Resource {
+	var Users []*iam.User
+	err := svc.ListUsersPages(&iam.ListUsersInput{}, func(page *iam.ListUsersOutput, lastPage bool) bool {
+		Users = append(Users, page.Users...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load IAM Users %v\n", err)
+		return nil
+	}
+	var Resources []Resource
+	for _, User := range Users {
+		Tags := make(map[string]string)
+		input := &iam.ListUserTagsInput{
+			UserName: User.UserName,
+		}
+		err := svc.ListUserTagsPages(input, func(page *iam.ListUserTagsOutput, lastPage bool) bool {
+			GetIAMTags(page.Tags, Tags)
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *User.UserName, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type:      "iam-user",
+			ID:        *User.UserName,
+			Arn:       aws.StringValue(User.Arn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(User.CreateDate),
+		})
+	}
+	return Resources
+}
+
+func ListIAMPolicies(svc *iam.IAM) []Resource {
+	// Only customer managed policies, AWS managed policies cannot be tagged.
+	var Policies []*iam.Policy
+	input := &iam.ListPoliciesInput{
+		Scope: aws.String(iam.PolicyScopeTypeLocal),
+	}
+	err := svc.ListPoliciesPages(input, func(page *iam.ListPoliciesOutput, lastPage bool) bool {
+		Policies = append(Policies, page.Policies...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load IAM Policies %v\n", err)
+		return nil
+	}
+	var Resources []Resource
+Policies:
+	for _, IAMPolicy := range Policies {
+		Tags := make(map[string]string)
+		input := &iam.ListPolicyTagsInput{
+			PolicyArn: IAMPolicy.Arn,
+		}
+		for {
+			result, err := svc.ListPolicyTags(input)
+			if err != nil {
+				fmt.Printf("Unable to load tags for %s %v\n", *IAMPolicy.PolicyName, err)
+				continue Policies
+			}
+			GetIAMTags(result.Tags, Tags)
+			if !aws.BoolValue(result.IsTruncated) {
+				break
+			}
+			input.Marker = result.Marker
+		}
+		Resources = append(Resources, Resource{
+			Type:      "iam-policy",
+			ID:        *IAMPolicy.PolicyName,
+			Arn:       aws.StringValue(IAMPolicy.Arn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(IAMPolicy.CreateDate),
+		})
+	}
+	return Resources
+}
+
+func IAMRoleInit(PolicyObject *Policy, sess *session.Session) {
+	Resources := GetGlobalResources("iam-role", func() []Resource {
+		return ListIAMRoles(iam.New(sess))
+	})
+	Tagged, UnTagged := ResourceFinder(Resources, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func IAMUserInit(PolicyObject *Policy, sess *session.Session) {
+	Resources := GetGlobalResources("iam-user", func() []Resource {
+		return ListIAMUsers(iam.New(sess))
+	})
+	Tagged, UnTagged := ResourceFinder(Resources, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func IAMPolicyInit(PolicyObject *Policy, sess *session.Session) {
+	Resources := GetGlobalResources("iam-policy", func() []Resource {
+		return ListIAMPolicies(iam.New(sess))
+	})
+	Tagged, UnTagged := ResourceFinder(Resources, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// Inherit modes. With satisfy a key the resource lacks counts as present
+// when its parent carries it, with copy the key is still reported missing and
+// remediation copies the parent's value.
+const (
+	InheritSatisfy = "satisfy"
+	InheritCopy    = "copy"
+)
+
+// ParentResource is a resource another resource belongs to, e.g. the
+// instance a volume is attached to or the VPC of a NAT gateway. Tags are only
+// looked up when the policy inherits tags.
+type ParentResource struct {
+	Type string
+	ID   string
+	Tags map[string]string
+}
+
+func (Parent ParentResource) Resource() Resource {
+	return Resource{Type: Parent.Type, ID: Parent.ID, Tags: Parent.Tags}
+}
+
+// InheritedTag is a key the resource lacks and its parent carries.
+type InheritedTag struct {
+	Key    string
+	Value  string
+	Parent ParentResource
+}
+
+func (Inherited InheritedTag) String() string {
+	return fmt.Sprintf("%s=%s from %s %s", Inherited.Key, Inherited.Value, Inherited.Parent.Type, Inherited.Parent.ID)
+}
+
+// FindInheritedTag looks the key up on the resource's parents, in order.
+func (Rule PolicyRule) FindInheritedTag(Resource Resource, PolicyTag PolicyKey) (InheritedTag, bool) {
+	for _, Parent := range Resource.Parents {
+		if _, Value, ok := Rule.FindTag(Parent.Resource(), PolicyTag); ok {
+			return InheritedTag{Key: PolicyTag.Key, Value: Value, Parent: Parent}, true
+		}
+	}
+	return InheritedTag{}, false
+}
+
+// DescribeTags accepts up to 200 resource IDs per filter.
+const EC2DescribeTagsLimit = 200
+
+// GetEC2TagsById returns the tags of EC2 resources of any type by ID.
+func GetEC2TagsById(svc *ec2.EC2, IDs []string) map[string]map[string]string {
+	TagMap := make(map[string]map[string]string)
+	for start := 0; start < len(IDs); start += EC2DescribeTagsLimit {
+		end := start + EC2DescribeTagsLimit
+		if end > len(IDs) {
+			end = len(IDs)
+		}
+		input := &ec2.DescribeTagsInput{
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("resource-id"),
+				Values: aws.StringSlice(IDs[start:end]),
+			}},
+		}
+		err := svc.DescribeTagsPages(input, func(page *ec2.DescribeTagsOutput, lastPage bool) bool {
+			for _, Tag := range page.Tags {
+				ID := aws.StringValue(Tag.ResourceId)
+				if TagMap[ID] == nil {
+					TagMap[ID] = make(map[string]string)
+				}
+				TagMap[ID][aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+			}
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load parent tags %v\n", err)
+		}
+	}
+	return TagMap
+}
+
+func GetParentIds(Resources []Resource) []string {
+	var IDs []string
+	for _, Resource := range Resources {
+		for _, Parent := range Resource.Parents {
+			if !ContainsString(IDs, Parent.ID) {
+				IDs = append(IDs, Parent.ID)
+			}
+		}
+	}
+	return IDs
+}
+
+func SetParentTags(Resources []Resource, TagMap map[string]map[string]string) {
+	for _, Resource := range Resources {
+		for index := range Resource.Parents {
+			Resource.Parents[index].Tags = TagMap[Resource.Parents[index].ID]
+		}
+	}
+}
+
+// ResolveEC2Parents looks up the tags of parents that are EC2 resources, only
+// when the policy inherits tags.
+func ResolveEC2Parents(svc *ec2.EC2, Resources []Resource, Rule PolicyRule) {
+	if Rule.Inherit == "" {
+		return
+	}
+	SetParentTags(Resources, GetEC2TagsById(svc, GetParentIds(Resources)))
+}
+
+// ResolveElbv2Parents looks up the tags of parent load balancers, by ARN.
+func ResolveElbv2Parents(svc *elbv2.ELBV2, Resources []Resource, Rule PolicyRule) {
+	if Rule.Inherit == "" {
+		return
+	}
+	SetParentTags(Resources, GetElbv2Tags(svc, aws.StringSlice(GetParentIds(Resources))))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+)
+
+func KinesisStreamFinder(svc *kinesis.Kinesis, StreamNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+Streams:
+	for _, StreamName := range StreamNames {
+		Summary, err := svc.DescribeStreamSummary(&kinesis.DescribeStreamSummaryInput{
+			StreamName: StreamName,
+		})
+		if err != nil {
+			fmt.Printf("Unable to describe Kinesis Stream %s %v\n", *StreamName, err)
+			continue
+		}
+		// ListTagsForStream pages on the last tag key returned.
+		Tags := make(map[string]string)
+		input := &kinesis.ListTagsForStreamInput{
+			StreamName: StreamName,
+		}
+		for {
+			result, err := svc.ListTagsForStream(input)
+			if err != nil {
+				fmt.Printf("Unable to load tags for %s %v\n", *StreamName, err)
+				continue Streams
+			}
+			for _, Tag := range result.Tags {
+				Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+			}
+			if !aws.BoolValue(result.HasMoreTags) || len(result.Tags) == 0 {
+				break
+			}
+			input.ExclusiveStartTagKey = result.Tags[len(result.Tags)-1].Key
+		}
+		Resources = append(Resources, Resource{
+			Type:      "kinesis-stream",
+			ID:        *StreamName,
+			Arn:       aws.StringValue(Summary.StreamDescriptionSummary.StreamARN),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Summary.StreamDescriptionSummary.StreamCreationTimestamp),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func KinesisStreamInit(PolicyObject *Policy, sess *session.Session) {
+	svc := kinesis.New(sess)
+	var StreamNames []*string
+	err := svc.ListStreamsPages(&kinesis.ListStreamsInput{}, func(page *kinesis.ListStreamsOutput, lastPage bool) bool {
+		StreamNames = append(StreamNames, page.StreamNames...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Kinesis Streams %v\n", err)
+		return
+	}
+	if len(StreamNames) == 0 {
+		fmt.Printf("No Kinesis Streams for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := KinesisStreamFinder(svc, StreamNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func FirehoseFinder(svc *firehose.Firehose, DeliveryStreamNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+DeliveryStreams:
+	for _, DeliveryStreamName := range DeliveryStreamNames {
+		Description, err := svc.DescribeDeliveryStream(&firehose.DescribeDeliveryStreamInput{
+			DeliveryStreamName: DeliveryStreamName,
+		})
+		if err != nil {
+			fmt.Printf("Unable to describe Delivery Stream %s %v\n", *DeliveryStreamName, err)
+			continue
+		}
+		// ListTagsForDeliveryStream pages on the last tag key returned.
+		Tags := make(map[string]string)
+		input := &firehose.ListTagsForDeliveryStreamInput{
+			DeliveryStreamName: DeliveryStreamName,
+		}
+		for {
+			result, err := svc.ListTagsForDeliveryStream(input)
+			if err != nil {
+				fmt.Printf("Unable to load tags for %s %v\n", *DeliveryStreamName, err)
+				continue DeliveryStreams
+			}
+			for _, Tag := range result.Tags {
+				Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+			}
+			if !aws.BoolValue(result.HasMoreTags) || len(result.Tags) == 0 {
+				break
+			}
+			input.ExclusiveStartTagKey = result.Tags[len(result.Tags)-1].Key
+		}
+		Resources = append(Resources, Resource{
+			Type:      "firehose-deliverystream",
+			ID:        *DeliveryStreamName,
+			Arn:       aws.StringValue(Description.DeliveryStreamDescription.DeliveryStreamARN),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Description.DeliveryStreamDescription.CreateTimestamp),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func FirehoseInit(PolicyObject *Policy, sess *session.Session) {
+	svc := firehose.New(sess)
+	// ListDeliveryStreams pages on the last stream name returned.
+	var DeliveryStreamNames []*string
+	input := &firehose.ListDeliveryStreamsInput{}
+	for {
+		result, err := svc.ListDeliveryStreams(input)
+		if err != nil {
+			fmt.Printf("Unable to load Delivery Streams %v\n", err)
+			return
+		}
+		DeliveryStreamNames = append(DeliveryStreamNames, result.DeliveryStreamNames...)
+		if !aws.BoolValue(result.HasMoreDeliveryStreams) || len(result.DeliveryStreamNames) == 0 {
+			break
+		}
+		input.ExclusiveStartDeliveryStreamName = result.DeliveryStreamNames[len(result.DeliveryStreamNames)-1]
+	}
+	if len(DeliveryStreamNames) == 0 {
+		fmt.Printf("No Delivery Streams for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := FirehoseFinder(svc, DeliveryStreamNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+func KMSKeyFinder(svc *kms.KMS, KeyList []*kms.KeyListEntry, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Key := range KeyList {
+		result, err := svc.DescribeKey(&kms.DescribeKeyInput{
+			KeyId: Key.KeyId,
+		})
+		if err != nil {
+			fmt.Printf("Unable to describe KMS Key %s %v\n", *Key.KeyId, err)
+			continue
+		}
+		// AWS managed keys cannot be tagged.
+		if aws.StringValue(result.KeyMetadata.KeyManager) == kms.KeyManagerTypeAws {
+			continue
+		}
+		Tags := make(map[string]string)
+		input := &kms.ListResourceTagsInput{
+			KeyId: Key.KeyId,
+		}
+		err = svc.ListResourceTagsPages(input, func(page *kms.ListResourceTagsOutput, lastPage bool) bool {
+			for _, Tag := range page.Tags {
+				Tags[aws.StringValue(Tag.TagKey)] = aws.StringValue(Tag.TagValue)
+			}
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Key.KeyId, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type:      "aws-kms-key",
+			ID:        *Key.KeyId,
+			Arn:       aws.StringValue(Key.KeyArn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(result.KeyMetadata.CreationDate),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func KMSKeyInit(PolicyObject *Policy, sess *session.Session) {
+	svc := kms.New(sess)
+	var Keys []*kms.KeyListEntry
+	err := svc.ListKeysPages(&kms.ListKeysInput{}, func(page *kms.ListKeysOutput, lastPage bool) bool {
+		Keys = append(Keys, page.Keys...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load KMS Keys %v\n", err)
+		return
+	}
+	if len(Keys) == 0 {
+		fmt.Printf("No KMS Keys for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := KMSKeyFinder(svc, Keys, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
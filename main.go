@@ -2,11 +2,15 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
@@ -17,36 +21,164 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/workspaces"
-	"gopkg.in/yaml.v2"
 )
 
+type PolicyRule struct {
+	Name           string        `yaml:"name"`
+	Extend         string        `yaml:"extend,omitempty"`
+	Abstract       bool          `yaml:"abstract,omitempty"`
+	Resources      []string      `yaml:"resources,omitempty"`
+	Caseinsenstive *bool         `yaml:"caseinsenstive,omitempty"`
+	Keys           []PolicyKey   `yaml:"keys,omitempty"`
+	Checks         []CustomCheck `yaml:"checks,omitempty"`
+	InstanceStates []string      `yaml:"instancestates,omitempty"`
+	Selector       Selector      `yaml:"selector,omitempty"`
+	Suppression    Suppression   `yaml:"suppression,omitempty"`
+	GracePeriod    string        `yaml:"graceperiod,omitempty"`
+	Severity       string        `yaml:"severity,omitempty"`
+	Mode           string        `yaml:"mode,omitempty"`
+	Inherit        string        `yaml:"inherit,omitempty"`
+	Quarantine     Quarantine    `yaml:"quarantine,omitempty"`
+	// File and Line locate the policy in its policy file for error messages.
+	File string `yaml:"-"`
+	Line int    `yaml:"-"`
+}
+
+// Policy is a policy file. Include lists further policy files, or directories
+// of them, whose policies are loaded before the file's own.
 type Policy struct {
-	Policy []struct {
-		Name           string   `yaml:"name"`
-		Resources      []string `yaml:"resources"`
-		Caseinsenstive bool     `yaml:"caseinsenstive"`
-		Keys           []string `yaml:"keys"`
-	} `yaml:"policy"`
+	Include []string     `yaml:"include,omitempty"`
+	Policy  []PolicyRule `yaml:"policy"`
 }
 
+// GetPolicyData returns the effective policy of a policy file: its includes
+// loaded, extended policies merged and abstract policies left out.
 func GetPolicyData(filePath string) *Policy {
-	// Read Policy Config file.
-	yamlFile, err := ioutil.ReadFile(filePath)
+	Rules, err := LoadPolicyFile(filePath, make(map[string]bool))
 	if err != nil {
-		log.Printf("yamlFile.Get err   #%v ", err)
+		log.Fatal(err)
+	}
+	Rules, err = ResolvePolicyRules(Rules)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(Rules) == 0 {
+		log.Fatalf("%s: no policies to check, the file and its includes only define abstract ones or none", filePath)
 	}
+	if Errors := ValidatePolicyRules(Rules); len(Errors) > 0 {
+		for _, err := range Errors {
+			log.Println(err)
+		}
+		log.Fatalf("%s: %d policy errors", filePath, len(Errors))
+	}
+	data := &Policy{Policy: Rules}
+	return data
+}
+
+// ParseGracePeriod parses a duration such as "30m", "6h" or "2d". Days are
+// accepted on top of the units of time.ParseDuration.
+func ParseGracePeriod(Value string) (time.Duration, error) {
+	if Value == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(Value, "d") {
+		Days, err := strconv.Atoi(strings.TrimSuffix(Value, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", Value)
+		}
+		return time.Duration(Days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(Value)
+}
+
+func (Rule PolicyRule) GetGracePeriod() time.Duration {
+	GracePeriod, _ := ParseGracePeriod(Rule.GracePeriod)
+	return GracePeriod
+}
 
-	var data *Policy
-	data_err := yaml.Unmarshal(yamlFile, &data)
+// PolicyKey is a tag key the policy requires. It is written as the plain key,
+// or as a mapping with a when condition when the key is only required for
+// some resources. when matches like a selector entry and can also match on the
+// resource type, region and account:
+//
+//	keys:
+//	- "Team"
+//	- key: "DataClassification"
+//	  when:
+//	    tags:
+//	      Environment: prod
+//	- key: "BackupPolicy"
+//	  severity: high
+//	  when:
+//	    types: ["ec2-volume", "rds"]
+//	- key: "CostCenter"
+//	  values: ["100", "200", "300*"]
+//	- key: "Team"
+//	  aliases: ["team", "owner_team", "TeamName"]
+//	- key: "Environment"
+//	  default: "dev"
+//
+// Values, when set, are the allowed tag values, with * matching any run of
+// characters. Aliases are other keys accepted in place of the key, which is
+// the canonical form the report suggests renaming them to. Default is the
+// value remediation tags resources missing the key with, see ExpandDefault.
+type PolicyKey struct {
+	Key      string        `yaml:"key"`
+	When     ResourceMatch `yaml:"when,omitempty"`
+	Severity string        `yaml:"severity,omitempty"`
+	Values   []string      `yaml:"values,omitempty"`
+	Aliases  []string      `yaml:"aliases,omitempty"`
+	Default  string        `yaml:"default,omitempty"`
+}
 
-	if data_err != nil {
-		log.Fatal(data_err)
+func (PolicyTag *PolicyKey) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&PolicyTag.Key); err == nil {
+		return nil
 	}
-	return data
+	type plain PolicyKey
+	return unmarshal((*plain)(PolicyTag))
+}
+
+func (PolicyTag PolicyKey) MarshalYAML() (interface{}, error) {
+	// Keys without options are written back as the plain key.
+	if PolicyTag.When.IsEmpty() && PolicyTag.Severity == "" && len(PolicyTag.Values) == 0 && len(PolicyTag.Aliases) == 0 && PolicyTag.Default == "" {
+		return PolicyTag.Key, nil
+	}
+	type plain PolicyKey
+	return plain(PolicyTag), nil
+}
+
+// Required reports whether the resource must carry the key. A key without a
+// when condition is required on every resource.
+func (PolicyTag PolicyKey) Required(Resource Resource) bool {
+	return PolicyTag.When.Matches(Resource)
+}
+
+func (PolicyTag PolicyKey) Allows(Value string) bool {
+	return len(PolicyTag.Values) == 0 || MatchAnyPattern(PolicyTag.Values, Value)
 }
 
 func GetPolicyKeys(PolicyObject *Policy) []string {
-	return PolicyObject.Policy[0].Keys
+	var KeyList []string
+	for _, PolicyTag := range PolicyObject.Policy[0].Keys {
+		KeyList = append(KeyList, PolicyTag.Key)
+	}
+	return KeyList
+}
+
+func GetPolicyRule(PolicyObject *Policy) PolicyRule {
+	return PolicyObject.Policy[0]
+}
+
+// DefaultInstanceStates is every EC2 instance state except terminated, so
+// stopped instances, which still carry EBS costs, are checked too.
+var DefaultInstanceStates = []string{"pending", "running", "shutting-down", "stopping", "stopped"}
+
+func GetPolicyInstanceStates(PolicyObject *Policy) []string {
+	if len(PolicyObject.Policy[0].InstanceStates) == 0 {
+		return DefaultInstanceStates
+	}
+	return PolicyObject.Policy[0].InstanceStates
 }
 
 func ListBucket(svc *s3.S3) (buckets []*s3.Bucket) {
@@ -73,20 +205,24 @@ func VarCheck() {
 	}
 }
 
-func GetS3TagKeys(svc *s3.S3, S3Bucket string) []string {
-	// Extract Keys from TagList
+func GetS3Tags(svc *s3.S3, S3Bucket string) (map[string]string, error) {
+	// Buckets without tags return a NoSuchTagSet error, any other error
+	// such as AccessDenied leaves the tags unknown.
 	TagInput := s3.GetBucketTaggingInput{
 		Bucket: &S3Bucket,
 	}
+	Tags := make(map[string]string)
 	TagSet, err := svc.GetBucketTagging(&TagInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchTagSet" {
+		return Tags, nil
+	}
 	if err != nil {
-		fmt.Println("Error:", err)
+		return nil, err
 	}
-	var KeyList []string
-	for value := range TagSet.TagSet {
-		KeyList = append(KeyList, *TagSet.TagSet[value].Key)
+	for _, Tag := range TagSet.TagSet {
+		Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
 	}
-	return KeyList
+	return Tags, nil
 }
 
 func GetBucketNameList(Buckets []*s3.Bucket) []string {
@@ -108,35 +244,31 @@ func contains(elems []string, v string) bool {
 	return ContainsFlag
 }
 
-func S3TagFinder(svc *s3.S3, BucketNameList, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	var TaggedS3Bucket, UnTaggedS3Bucket []string
-	for _, S3Bucket := range BucketNameList {
-		fmt.Println("\n\n")
-		fmt.Println("Bucket Name: ", S3Bucket)
-		S3BucketTagList := GetS3TagKeys(svc, S3Bucket)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(S3BucketTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedS3Bucket = append(UnTaggedS3Bucket, S3Bucket)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				fmt.Println("TagCheck Passed.")
-				TaggedS3Bucket = append(TaggedS3Bucket, S3Bucket)
-			}
+func S3TagFinder(svc *s3.S3, Buckets []*s3.Bucket, Rule PolicyRule) ([]string, []string) {
+	Partition := GetPartition(aws.StringValue(svc.Config.Region))
+	var Resources []Resource
+	for _, Bucket := range Buckets {
+		S3Bucket := *Bucket.Name
+		Tags, err := GetS3Tags(svc, S3Bucket)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", S3Bucket, err)
+			continue
 		}
+		Resources = append(Resources, Resource{
+			Type:      "s3",
+			ID:        S3Bucket,
+			Arn:       "arn:" + Partition + ":s3:::" + S3Bucket,
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Bucket.CreationDate),
+		})
 	}
-	return TaggedS3Bucket, UnTaggedS3Bucket
+	return ResourceFinder(Resources, Rule)
 }
 
 func S3Init(PolicyObject *Policy, sess *session.Session) {
 	svc := s3.New(sess)
 	Buckets := ListBucket(svc)
-	BucketNameList := GetBucketNameList(Buckets)
-	Tagged, UnTagged := S3TagFinder(svc, BucketNameList, GetPolicyKeys(PolicyObject))
+	Tagged, UnTagged := S3TagFinder(svc, Buckets, GetPolicyRule(PolicyObject))
 
 	fmt.Println("Final Tagged:", Tagged, "Final UnTagged:", UnTagged)
 }
@@ -157,28 +289,32 @@ func GetEc2TagKeys(ec2List *ec2.Instance) []string {
 	return KeyList
 }
 
-func Ec2TagFinder(Ec2List []*ec2.Instance, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	var TaggedEC2Instances, UnTaggedEC2Instances []string
+func GetEc2Tags(TagList []*ec2.Tag) map[string]string {
+	Tags := make(map[string]string)
+	for _, Tag := range TagList {
+		Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+	}
+	return Tags
+}
+
+func Ec2TagFinder(Ec2List []*ec2.Instance, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, EC2Instance := range Ec2List {
-		fmt.Println("\n\n")
-		fmt.Println("Instance Name: ", EC2Instance.InstanceId)
-		Ec2TagList := GetEc2TagKeys(EC2Instance)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(Ec2TagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedEC2Instances = append(UnTaggedEC2Instances, *EC2Instance.InstanceId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				fmt.Println("TagCheck Passed.")
-				TaggedEC2Instances = append(TaggedEC2Instances, *EC2Instance.InstanceId)
-			}
-		}
+		Resources = append(Resources, Resource{
+			Type:      "ec2",
+			ID:        *EC2Instance.InstanceId,
+			Tags:      GetEc2Tags(EC2Instance.Tags),
+			CreatedAt: aws.TimeValue(EC2Instance.LaunchTime),
+			Attributes: map[string]string{
+				"State":        aws.StringValue(EC2Instance.State.Name),
+				"LaunchTime":   aws.TimeValue(EC2Instance.LaunchTime).Format(time.RFC3339),
+				"VpcId":        aws.StringValue(EC2Instance.VpcId),
+				"SubnetId":     aws.StringValue(EC2Instance.SubnetId),
+				"InstanceType": aws.StringValue(EC2Instance.InstanceType),
+			},
+		})
 	}
-	return TaggedEC2Instances, UnTaggedEC2Instances
+	return ResourceFinder(Resources, Rule)
 }
 
 func EC2Init(PolicyObject *Policy, sess *session.Session) {
@@ -186,29 +322,28 @@ func EC2Init(PolicyObject *Policy, sess *session.Session) {
 	params := &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			&ec2.Filter{
-				Name: aws.String("instance-state-name"),
-				Values: []*string{
-					aws.String("running"),
-					aws.String("pending"),
-				},
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice(GetPolicyInstanceStates(PolicyObject)),
 			},
 		},
 	}
 
-	// TODO: Actually care if we can't connect to a host
-	resp, err := svc.DescribeInstances(params)
-	check(err)
-
 	var InstancesList []*ec2.Instance
-	for idx, _ := range resp.Reservations {
-		InstancesList = append(InstancesList, resp.Reservations[idx].Instances...)
-		for _, inst := range resp.Reservations[idx].Instances {
-			fmt.Println("    - Instance ID: ", *inst.InstanceId)
+	err := svc.DescribeInstancesPages(params, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, Reservation := range page.Reservations {
+			InstancesList = append(InstancesList, Reservation.Instances...)
 		}
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Instances %v\n", err)
+		return
+	}
+	for _, inst := range InstancesList {
+		fmt.Println("    - Instance ID: ", *inst.InstanceId, " State: ", aws.StringValue(inst.State.Name))
 	}
 	fmt.Println(len(InstancesList))
-	fmt.Println("Instances List:", InstancesList)
-	Tagged, UnTagged := Ec2TagFinder(InstancesList, GetPolicyKeys(PolicyObject))
+	Tagged, UnTagged := Ec2TagFinder(InstancesList, GetPolicyRule(PolicyObject))
 	fmt.Println("Final Tagged:", Tagged)
 	fmt.Println("Final UnTagged:", UnTagged)
 }
@@ -222,231 +357,402 @@ func GetElbTagKeys(tagList []*elb.Tag) []string {
 	return KeyList
 }
 
-func ElbTagFinder(svc *elb.ELB, ElbList []*elb.LoadBalancerDescription, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	var TaggedElb, UnTaggedElb, ElbTagList []string
-	var LoadBalancerNamesList []*string
+func ElbTagFinder(svc *elb.ELB, ElbList []*elb.LoadBalancerDescription, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, Elb := range ElbList {
-		fmt.Println("\n\n")
-		fmt.Println("ELB Name: ", *Elb.LoadBalancerName)
-		LoadBalancerNamesList = append(LoadBalancerNamesList, Elb.LoadBalancerName)
 		TagInputs := elb.DescribeTagsInput{
-			LoadBalancerNames: LoadBalancerNamesList,
+			LoadBalancerNames: []*string{Elb.LoadBalancerName},
 		}
 		ELB_Tags, err := svc.DescribeTags(&TagInputs)
-		check(err)
-		for _, val := range ELB_Tags.TagDescriptions {
-			ElbTagList = GetElbTagKeys(val.Tags)
-			fmt.Println(ElbTagList)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Elb.LoadBalancerName, err)
+			continue
 		}
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(ElbTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedElb = append(TaggedElb, *Elb.LoadBalancerName)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedElb = append(UnTaggedElb, *Elb.LoadBalancerName)
+		Tags := make(map[string]string)
+		for _, val := range ELB_Tags.TagDescriptions {
+			for _, Tag := range val.Tags {
+				Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
 			}
 		}
-		LoadBalancerNamesList = nil
+		Resources = append(Resources, Resource{
+			Type:      "elb",
+			ID:        *Elb.LoadBalancerName,
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Elb.CreatedTime),
+			Attributes: map[string]string{
+				"VpcId": aws.StringValue(Elb.VPCId),
+			},
+		})
 	}
-	return TaggedElb, UnTaggedElb
+	return ResourceFinder(Resources, Rule)
 }
 
 func ELBInit(PolicyObject *Policy, sess *session.Session) {
 
 	svc := elb.New(sess)
 	input := &elb.DescribeLoadBalancersInput{}
-	result, _ := svc.DescribeLoadBalancers(input)
-	Tagged, UnTagged := ElbTagFinder(svc, result.LoadBalancerDescriptions, GetPolicyKeys(PolicyObject))
+	result, err := svc.DescribeLoadBalancers(input)
+	if err != nil {
+		fmt.Printf("Unable to load ELBs %v\n", err)
+		return
+	}
+	Tagged, UnTagged := ElbTagFinder(svc, result.LoadBalancerDescriptions, GetPolicyRule(PolicyObject))
 
 	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 	fmt.Println("Final UnTagged:", UnTagged)
 }
 
-func GetElbTargetGroupTagKeys(tagList []*elbv2.Tag) []string {
-	var KeyList []string
-
-	for index := range tagList {
-		KeyList = append(KeyList, *tagList[index].Key)
+// Elbv2DescribeTagsLimit is the maximum number of ARNs accepted by a single
+// elbv2 DescribeTags call.
+const Elbv2DescribeTagsLimit = 20
+
+func GetElbv2Tags(svc *elbv2.ELBV2, ArnList []*string) map[string]map[string]string {
+	// DescribeTags for load balancers, target groups, listeners and rules in batches.
+	// Resources of a failed batch are left out, the finders skip them.
+	TagMap := make(map[string]map[string]string)
+	for start := 0; start < len(ArnList); start += Elbv2DescribeTagsLimit {
+		end := start + Elbv2DescribeTagsLimit
+		if end > len(ArnList) {
+			end = len(ArnList)
+		}
+		TagInputs := elbv2.DescribeTagsInput{
+			ResourceArns: ArnList[start:end],
+		}
+		Elbv2Tags, err := svc.DescribeTags(&TagInputs)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %d resources %v\n", end-start, err)
+			continue
+		}
+		for _, val := range Elbv2Tags.TagDescriptions {
+			Tags := make(map[string]string)
+			for _, Tag := range val.Tags {
+				Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+			}
+			TagMap[aws.StringValue(val.ResourceArn)] = Tags
+		}
 	}
-	return KeyList
+	return TagMap
 }
 
-func ElbTargetGroupFinder(svc *elbv2.ELBV2, ElbTargetGroupList []*elbv2.TargetGroup, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	var TaggedElbTargetGroup, UnTaggedElbTargetGroup, ElbTargetGroupTagList []string
+func ElbTargetGroupFinder(svc *elbv2.ELBV2, ElbTargetGroupList []*elbv2.TargetGroup, Rule PolicyRule) ([]string, []string) {
 	var ElbTargetGroupArnList []*string
 	for _, ElbTargetGroup := range ElbTargetGroupList {
-		fmt.Println("Name: ", *ElbTargetGroup.TargetGroupName)
-		fmt.Println("Arn: ", *ElbTargetGroup.TargetGroupArn)
-		ElbTargetGroupArnList = append(ElbTargetGroupArnList, *&ElbTargetGroup.TargetGroupArn)
-		TagInputs := elbv2.DescribeTagsInput{
-			ResourceArns: ElbTargetGroupArnList,
-		}
-		ElbTargetGroup_Tags, err := svc.DescribeTags(&TagInputs)
-		check(err)
-		for _, val := range ElbTargetGroup_Tags.TagDescriptions {
-			ElbTargetGroupTagList = GetElbTargetGroupTagKeys(val.Tags)
-			fmt.Println(ElbTargetGroupTagList)
+		ElbTargetGroupArnList = append(ElbTargetGroupArnList, ElbTargetGroup.TargetGroupArn)
+	}
+	TagMap := GetElbv2Tags(svc, ElbTargetGroupArnList)
+
+	var Resources []Resource
+	for _, ElbTargetGroup := range ElbTargetGroupList {
+		Tags, ok := TagMap[*ElbTargetGroup.TargetGroupArn]
+		if !ok {
+			continue
 		}
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(ElbTargetGroupTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedElbTargetGroup = append(UnTaggedElbTargetGroup, *ElbTargetGroup.TargetGroupName)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedElbTargetGroup = append(TaggedElbTargetGroup, *ElbTargetGroup.TargetGroupName)
-			}
+		Resources = append(Resources, Resource{
+			Type: "elb-targetgroup",
+			ID:   *ElbTargetGroup.TargetGroupName,
+			Arn:  *ElbTargetGroup.TargetGroupArn,
+			Tags: Tags,
+		})
+		for _, LoadBalancerArn := range ElbTargetGroup.LoadBalancerArns {
+			Resources[len(Resources)-1].Parents = append(Resources[len(Resources)-1].Parents, ParentResource{Type: "elbv2", ID: aws.StringValue(LoadBalancerArn)})
 		}
 	}
-
-	return TaggedElbTargetGroup, UnTaggedElbTargetGroup
+	ResolveElbv2Parents(svc, Resources, Rule)
+	return ResourceFinder(Resources, Rule)
 }
 
 func ElbTargetGroupInit(PolicyObject *Policy, sess *session.Session) {
 	svc := elbv2.New(sess)
+	var TargetGroups []*elbv2.TargetGroup
 	input := &elbv2.DescribeTargetGroupsInput{}
-	result, _ := svc.DescribeTargetGroups(input)
-	Tagged, UnTagged := ElbTargetGroupFinder(svc, result.TargetGroups, GetPolicyKeys(PolicyObject))
+	err := svc.DescribeTargetGroupsPages(input, func(page *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool {
+		TargetGroups = append(TargetGroups, page.TargetGroups...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Target Groups %v\n", err)
+		return
+	}
+	Tagged, UnTagged := ElbTargetGroupFinder(svc, TargetGroups, GetPolicyRule(PolicyObject))
 	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 	fmt.Println("Final UnTagged:", UnTagged)
 }
 
-func LambdaFinder(svc *lambda.Lambda, LambdaList []*lambda.FunctionConfiguration, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("XXXX")
-	var TaggedLambda, UnTaggedLambda []string
-	var LambdaTagList []string
-	// var LambdaArnList []*string
-	for _, Lambda := range LambdaList {
-		fmt.Println("\n\n\nName: ", *Lambda.FunctionName)
-		fmt.Println("Arn: ", *Lambda.FunctionArn)
-		// LambdaArnList = append(LambdaArnList)
-		TagInputs := lambda.ListTagsInput{
-			Resource: *&Lambda.FunctionArn,
+func ListElbv2LoadBalancers(svc *elbv2.ELBV2) []*elbv2.LoadBalancer {
+	// Application, Network and Gateway Load Balancers.
+	var LoadBalancers []*elbv2.LoadBalancer
+	input := &elbv2.DescribeLoadBalancersInput{}
+	err := svc.DescribeLoadBalancersPages(input, func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+		LoadBalancers = append(LoadBalancers, page.LoadBalancers...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Load Balancers %v\n", err)
+		return nil
+	}
+	return LoadBalancers
+}
+
+func ListElbv2Listeners(svc *elbv2.ELBV2, LoadBalancers []*elbv2.LoadBalancer) []*elbv2.Listener {
+	var Listeners []*elbv2.Listener
+	for _, LoadBalancer := range LoadBalancers {
+		input := &elbv2.DescribeListenersInput{
+			LoadBalancerArn: LoadBalancer.LoadBalancerArn,
 		}
-		LambdaTags, err := svc.ListTags(&TagInputs)
-		fmt.Println(LambdaTags.Tags)
-		check(err)
-		for index, _ := range LambdaTags.Tags {
-			LambdaTagList = append(LambdaTagList, index)
+		err := svc.DescribeListenersPages(input, func(page *elbv2.DescribeListenersOutput, lastPage bool) bool {
+			Listeners = append(Listeners, page.Listeners...)
+			return true
+		})
+		if err != nil {
+			fmt.Printf("Unable to load Listeners for %s %v\n", *LoadBalancer.LoadBalancerName, err)
 		}
-		fmt.Println("-->", LambdaTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(LambdaTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedLambda = append(UnTaggedLambda, *Lambda.FunctionName)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedLambda = append(TaggedLambda, *Lambda.FunctionName)
-			}
+	}
+	return Listeners
+}
+
+func Elbv2Finder(svc *elbv2.ELBV2, LoadBalancerList []*elbv2.LoadBalancer, Rule PolicyRule) ([]string, []string) {
+	var LoadBalancerArnList []*string
+	for _, LoadBalancer := range LoadBalancerList {
+		LoadBalancerArnList = append(LoadBalancerArnList, LoadBalancer.LoadBalancerArn)
+	}
+	TagMap := GetElbv2Tags(svc, LoadBalancerArnList)
+
+	var Resources []Resource
+	for _, LoadBalancer := range LoadBalancerList {
+		Tags, ok := TagMap[*LoadBalancer.LoadBalancerArn]
+		if !ok {
+			continue
 		}
-		LambdaTagList = nil
+		Resources = append(Resources, Resource{
+			Type:      "elbv2",
+			ID:        *LoadBalancer.LoadBalancerName,
+			Arn:       *LoadBalancer.LoadBalancerArn,
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(LoadBalancer.CreatedTime),
+		})
 	}
-	return TaggedLambda, UnTaggedLambda
+	return ResourceFinder(Resources, Rule)
 }
 
-func RdsTagFinder(Ec2List []*ec2.Instance, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	var TaggedEC2Instances, UnTaggedEC2Instances []string
-	for _, EC2Instance := range Ec2List {
-		fmt.Println("\n\n")
-		fmt.Println("Instance Name: ", EC2Instance.InstanceId)
-		Ec2TagList := GetEc2TagKeys(EC2Instance)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(Ec2TagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedEC2Instances = append(UnTaggedEC2Instances, *EC2Instance.InstanceId)
+func Elbv2Init(PolicyObject *Policy, sess *session.Session) {
+	svc := elbv2.New(sess)
+	LoadBalancers := ListElbv2LoadBalancers(svc)
+	if len(LoadBalancers) == 0 {
+		fmt.Printf("No Load Balancers for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := Elbv2Finder(svc, LoadBalancers, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func Elbv2ListenerFinder(svc *elbv2.ELBV2, ListenerList []*elbv2.Listener, Rule PolicyRule) ([]string, []string) {
+	var ListenerArnList []*string
+	for _, Listener := range ListenerList {
+		ListenerArnList = append(ListenerArnList, Listener.ListenerArn)
+	}
+	TagMap := GetElbv2Tags(svc, ListenerArnList)
+
+	var Resources []Resource
+	for _, Listener := range ListenerList {
+		Tags, ok := TagMap[*Listener.ListenerArn]
+		if !ok {
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "elbv2-listener",
+			ID:   *Listener.ListenerArn,
+			Arn:  *Listener.ListenerArn,
+			Tags: Tags,
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func Elbv2ListenerInit(PolicyObject *Policy, sess *session.Session) {
+	svc := elbv2.New(sess)
+	Listeners := ListElbv2Listeners(svc, ListElbv2LoadBalancers(svc))
+	if len(Listeners) == 0 {
+		fmt.Printf("No Listeners for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := Elbv2ListenerFinder(svc, Listeners, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func Elbv2ListenerRuleFinder(svc *elbv2.ELBV2, RuleList []*elbv2.Rule, Rule PolicyRule) ([]string, []string) {
+	var RuleArnList []*string
+	for _, Rule := range RuleList {
+		RuleArnList = append(RuleArnList, Rule.RuleArn)
+	}
+	TagMap := GetElbv2Tags(svc, RuleArnList)
+
+	var Resources []Resource
+	for _, Rule := range RuleList {
+		Tags, ok := TagMap[*Rule.RuleArn]
+		if !ok {
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type: "elbv2-listener-rule",
+			ID:   *Rule.RuleArn,
+			Arn:  *Rule.RuleArn,
+			Tags: Tags,
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func Elbv2ListenerRuleInit(PolicyObject *Policy, sess *session.Session) {
+	svc := elbv2.New(sess)
+	var Rules []*elbv2.Rule
+	for _, Listener := range ListElbv2Listeners(svc, ListElbv2LoadBalancers(svc)) {
+		// DescribeRules has no paginator in the SDK, follow NextMarker by hand.
+		input := &elbv2.DescribeRulesInput{
+			ListenerArn: Listener.ListenerArn,
+		}
+		for {
+			result, err := svc.DescribeRules(input)
+			if err != nil {
+				fmt.Printf("Unable to load Rules for %s %v\n", *Listener.ListenerArn, err)
+				break
+			}
+			for _, Rule := range result.Rules {
+				// The default rule is part of the listener itself.
+				if aws.BoolValue(Rule.IsDefault) {
+					continue
+				}
+				Rules = append(Rules, Rule)
+			}
+			if result.NextMarker == nil {
 				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				fmt.Println("TagCheck Passed.")
-				TaggedEC2Instances = append(TaggedEC2Instances, *EC2Instance.InstanceId)
 			}
+			input.Marker = result.NextMarker
+		}
+	}
+	if len(Rules) == 0 {
+		fmt.Printf("No Listener Rules for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := Elbv2ListenerRuleFinder(svc, Rules, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
+
+func LambdaFinder(svc *lambda.Lambda, LambdaList []*lambda.FunctionConfiguration, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Lambda := range LambdaList {
+		TagInputs := lambda.ListTagsInput{
+			Resource: Lambda.FunctionArn,
 		}
+		LambdaTags, err := svc.ListTags(&TagInputs)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Lambda.FunctionName, err)
+			continue
+		}
+		Resources = append(Resources, Resource{
+			Type:      "lambda-functions",
+			ID:        *Lambda.FunctionName,
+			Arn:       *Lambda.FunctionArn,
+			Tags:      aws.StringValueMap(LambdaTags.Tags),
+			CreatedAt: ParseLambdaTime(aws.StringValue(Lambda.LastModified)),
+			Attributes: map[string]string{
+				"Runtime": aws.StringValue(Lambda.Runtime),
+			},
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func RDSFinder(DBInstanceList []*rds.DBInstance, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, DBInstance := range DBInstanceList {
+		Tags := make(map[string]string)
+		for _, Tag := range DBInstance.TagList {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "rds",
+			ID:        *DBInstance.DBInstanceIdentifier,
+			Arn:       aws.StringValue(DBInstance.DBInstanceArn),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(DBInstance.InstanceCreateTime),
+			Attributes: map[string]string{
+				"Engine":          aws.StringValue(DBInstance.Engine),
+				"DBInstanceClass": aws.StringValue(DBInstance.DBInstanceClass),
+				"Status":          aws.StringValue(DBInstance.DBInstanceStatus),
+			},
+		})
 	}
-	return TaggedEC2Instances, UnTaggedEC2Instances
+	return ResourceFinder(Resources, Rule)
 }
 
 func RDSInit(PolicyObject *Policy, sess *session.Session) {
 	svc := rds.New(sess)
-	input := rds.DescribeDBInstancesInput{}
-	result, err := svc.DescribeDBInstances(&input)
+	var DBInstances []*rds.DBInstance
+	err := svc.DescribeDBInstancesPages(&rds.DescribeDBInstancesInput{}, func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		DBInstances = append(DBInstances, page.DBInstances...)
+		return true
+	})
 	if err != nil {
-		fmt.Println("Cannot list functions")
-		os.Exit(0)
+		fmt.Printf("Unable to load RDS instances %v\n", err)
+		return
 	}
-	fmt.Println(result)
-	for _, f := range result.DBInstances {
-		fmt.Println(f)
+	if len(DBInstances) == 0 {
+		fmt.Printf("No RDS instances for %s region\n", *svc.Config.Region)
+		return
 	}
-	// Tagged, UnTagged := LambdaFinder(svc, result.Functions, GetPolicyKeys(PolicyObject))
-	// fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
-	// fmt.Println("Final UnTagged:", UnTagged)
+	Tagged, UnTagged := RDSFinder(DBInstances, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
 }
 
 func LambdaInit(PolicyObject *Policy, sess *session.Session) {
 	svc := lambda.New(sess)
-	fmt.Println(svc)
-	result, err := svc.ListFunctions(nil)
+	var Functions []*lambda.FunctionConfiguration
+	err := svc.ListFunctionsPages(&lambda.ListFunctionsInput{}, func(page *lambda.ListFunctionsOutput, lastPage bool) bool {
+		Functions = append(Functions, page.Functions...)
+		return true
+	})
 	if err != nil {
-		fmt.Println("Cannot list functions")
-		os.Exit(0)
+		fmt.Printf("Unable to load Lambda functions %v\n", err)
+		return
 	}
-	// for _, f := range result.Functions {
-	// 	fmt.Println("Name:        " + aws.StringValue(f.FunctionName))
-	// }
-	Tagged, UnTagged := LambdaFinder(svc, result.Functions, GetPolicyKeys(PolicyObject))
+	Tagged, UnTagged := LambdaFinder(svc, Functions, GetPolicyRule(PolicyObject))
 	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 	fmt.Println("Final UnTagged:", UnTagged)
 }
 
-func Route53Finder(svc *route53.Route53, Route53List []*route53.HostedZone, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("Route53")
-	var Route53TagList []string
-	var TaggedLambda, UnTaggedLambda []string
-	// var ResourceIds []*string
+func Route53Finder(svc *route53.Route53, Route53List []*route53.HostedZone, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	ResourceType := "hostedzone"
+	Partition := GetPartition(aws.StringValue(svc.Config.Region))
 	for _, Route53 := range Route53List {
-		fmt.Println("\n\n\nName: ", *Route53.Name)
-		fmt.Println("\n\n\nHostedID: ", *Route53.Id)
-
 		input := route53.ListTagsForResourceInput{
 			ResourceId:   Route53.Id,
 			ResourceType: &ResourceType,
 		}
-		tagObject, _ := svc.ListTagsForResource(&input)
-		for index := range tagObject.ResourceTagSet.Tags {
-			Route53TagList = append(Route53TagList, *tagObject.ResourceTagSet.Tags[index].Key)
+		tagObject, err := svc.ListTagsForResource(&input)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Route53.Name, err)
+			continue
 		}
-		fmt.Println("-->", Route53TagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(Route53TagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedLambda = append(UnTaggedLambda, *Route53.Name)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedLambda = append(TaggedLambda, *Route53.Name)
-			}
+		Tags := make(map[string]string)
+		for _, Tag := range tagObject.ResourceTagSet.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
 		}
+		Resources = append(Resources, Resource{
+			Type: "route53-hostedzone",
+			ID:   *Route53.Name,
+			Arn:  "arn:" + Partition + ":route53:::" + strings.TrimPrefix(*Route53.Id, "/"),
+			Tags: Tags,
+			Attributes: map[string]string{
+				"HostedZoneId": *Route53.Id,
+			},
+		})
 	}
-	return TaggedLambda, UnTaggedLambda
+	return ResourceFinder(Resources, Rule)
 }
 
 func Route53Init(PolicyObject *Policy, sess *session.Session) {
@@ -454,145 +760,142 @@ func Route53Init(PolicyObject *Policy, sess *session.Session) {
 	input := route53.ListHostedZonesInput{}
 	result, err := svc.ListHostedZones(&input)
 	if err != nil {
-		fmt.Println("Cannot list Route53")
-		os.Exit(0)
+		fmt.Printf("Unable to load Route53 hosted zones %v\n", err)
+		return
 	}
 
 	for _, f := range result.HostedZones {
 		fmt.Println(*f)
 	}
-	Tagged, UnTagged := Route53Finder(svc, result.HostedZones, GetPolicyKeys(PolicyObject))
+	Tagged, UnTagged := Route53Finder(svc, result.HostedZones, GetPolicyRule(PolicyObject))
 	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 	fmt.Println("Final UnTagged:", UnTagged)
 }
 
-func SQSFinder(svc *sqs.SQS, QueueUrls []*string, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("SQS")
-	var SQSTagList []string
-	var TaggedSQS, UnTaggedSQS []string
-	for _, URL := range QueueUrls {
+// GetQueueArn builds a queue's ARN from its URL,
+// https://sqs.<region>.amazonaws.com/<account>/<name>.
+func GetQueueArn(Region, URL string) string {
+	Parts := strings.Split(URL, "/")
+	if len(Parts) < 2 {
+		return ""
+	}
+	return arn.ARN{
+		Partition: GetPartition(Region),
+		Service:   "sqs",
+		Region:    Region,
+		AccountID: Parts[len(Parts)-2],
+		Resource:  Parts[len(Parts)-1],
+	}.String()
+}
 
-		// input := route53.ListTagsForResourceInput{
-		// 	ResourceId:   Route53.Id,
-		// 	ResourceType: &ResourceType,
-		// }
+func SQSFinder(svc *sqs.SQS, QueueUrls []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, URL := range QueueUrls {
 		input := sqs.ListQueueTagsInput{
 			QueueUrl: URL,
 		}
-		tagObject, _ := svc.ListQueueTags(&input)
-		for Tag := range tagObject.Tags {
-			SQSTagList = append(SQSTagList, Tag)
-		}
-		fmt.Println("-->", SQSTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(SQSTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedSQS = append(UnTaggedSQS, *URL)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedSQS = append(TaggedSQS, *URL)
-			}
+		tagObject, err := svc.ListQueueTags(&input)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *URL, err)
+			continue
 		}
+		Resources = append(Resources, Resource{
+			Type: "sqs",
+			ID:   *URL,
+			Arn:  GetQueueArn(*svc.Config.Region, *URL),
+			Tags: aws.StringValueMap(tagObject.Tags),
+		})
 	}
-	return TaggedSQS, UnTaggedSQS
+	return ResourceFinder(Resources, Rule)
 }
 
-func WorkspacesFinder(svc *workspaces.WorkSpaces, Workspaces []*workspaces.Workspace, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("Workspaces:")
-	var WorkspacesTagList []string
-	var TaggedWorkspaces, UnTaggedWorkspaces []string
+func WorkspacesFinder(svc *workspaces.WorkSpaces, Account *AccountIdentity, Workspaces []*workspaces.Workspace, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, Workspace := range Workspaces {
-
 		input := workspaces.DescribeTagsInput{
-			ResourceId: *&Workspace.WorkspaceId,
+			ResourceId: Workspace.WorkspaceId,
 		}
-		tagObject, _ := svc.DescribeTags(&input)
-		for index, _ := range tagObject.TagList {
-			WorkspacesTagList = append(WorkspacesTagList, *tagObject.TagList[index].Key)
+		tagObject, err := svc.DescribeTags(&input)
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Workspace.WorkspaceId, err)
+			continue
 		}
-		fmt.Println("-->", WorkspacesTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(WorkspacesTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedWorkspaces = append(UnTaggedWorkspaces, *Workspace.WorkspaceId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedWorkspaces = append(TaggedWorkspaces, *Workspace.WorkspaceId)
-			}
+		Tags := make(map[string]string)
+		for _, Tag := range tagObject.TagList {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
 		}
+		Resources = append(Resources, Resource{
+			Type: "workspaces",
+			ID:   *Workspace.WorkspaceId,
+			Arn:  Account.Arn("workspaces", *svc.Config.Region, "workspace/"+*Workspace.WorkspaceId),
+			Tags: Tags,
+			Attributes: map[string]string{
+				"UserName": aws.StringValue(Workspace.UserName),
+			},
+		})
 	}
-	return TaggedWorkspaces, UnTaggedWorkspaces
+	return ResourceFinder(Resources, Rule)
 }
 
 func SQSInit(PolicyObject *Policy, sess *session.Session) {
 	svc := sqs.New(sess)
-	input := sqs.ListQueuesInput{}
-	result, err := svc.ListQueues(&input)
+	var QueueUrls []*string
+	err := svc.ListQueuesPages(&sqs.ListQueuesInput{}, func(page *sqs.ListQueuesOutput, lastPage bool) bool {
+		QueueUrls = append(QueueUrls, page.QueueUrls...)
+		return true
+	})
 	if err != nil {
-		fmt.Println("Cannot list Route53")
-		os.Exit(0)
+		fmt.Printf("Unable to load SQS queues %v\n", err)
+		return
 	}
 
-	for _, f := range result.QueueUrls {
+	for _, f := range QueueUrls {
 		fmt.Println(*f)
 	}
 
-	Tagged, UnTagged := SQSFinder(svc, result.QueueUrls, GetPolicyKeys(PolicyObject))
+	Tagged, UnTagged := SQSFinder(svc, QueueUrls, GetPolicyRule(PolicyObject))
 	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 	fmt.Println("Final UnTagged:", UnTagged)
 }
 
 func WorkspacesInit(PolicyObject *Policy, sess *session.Session) {
 	svc := workspaces.New(sess)
+	// DescribeWorkspaces doesn't return ARNs, they are built from the account.
+	Account, err := GetAccountIdentity(sess)
+	if err != nil {
+		fmt.Printf("Unable to load account identity %v\n", err)
+		return
+	}
 	input := workspaces.DescribeWorkspacesInput{}
 	result, err := svc.DescribeWorkspaces(&input)
 	if err != nil {
-		fmt.Println("Cannot list Route53")
-		os.Exit(0)
+		fmt.Printf("Unable to load WorkSpaces %v\n", err)
+		return
 	}
 
 	for _, workspace := range result.Workspaces {
 		fmt.Println(*workspace)
 	}
 
-	Tagged, UnTagged := WorkspacesFinder(svc, result.Workspaces, GetPolicyKeys(PolicyObject))
+	Tagged, UnTagged := WorkspacesFinder(svc, Account, result.Workspaces, GetPolicyRule(PolicyObject))
 	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 	fmt.Println("Final UnTagged:", UnTagged)
 }
 
-func ElasticIpFinder(svc *ec2.EC2, ElasticIps []*ec2.Address, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("Workspaces:")
-	var ElasticIpTagList []string
-	var TaggedElasticIp, UnTaggedElasticIp []string
+func ElasticIpFinder(svc *ec2.EC2, ElasticIps []*ec2.Address, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, eip := range ElasticIps {
-		for index, _ := range eip.Tags {
-			ElasticIpTagList = append(ElasticIpTagList, *eip.Tags[index].Key)
-		}
-		fmt.Println("-->", ElasticIpTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(ElasticIpTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedElasticIp = append(UnTaggedElasticIp, *eip.AllocationId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedElasticIp = append(TaggedElasticIp, *eip.AllocationId)
-			}
-		}
-		ElasticIpTagList = nil
+		Resources = append(Resources, Resource{
+			Type: "ec2-eip",
+			ID:   *eip.AllocationId,
+			Tags: GetEc2Tags(eip.Tags),
+			Attributes: map[string]string{
+				"PublicIp":   aws.StringValue(eip.PublicIp),
+				"InstanceId": aws.StringValue(eip.InstanceId),
+			},
+		})
 	}
-	return TaggedElasticIp, UnTaggedElasticIp
+	return ResourceFinder(Resources, Rule)
 }
 
 func ElasticIpInit(PolicyObject *Policy, sess *session.Session) {
@@ -606,13 +909,14 @@ func ElasticIpInit(PolicyObject *Policy, sess *session.Session) {
 		},
 	})
 	if err != nil {
-		fmt.Print("Unable to elastic IP address, %v", err)
+		fmt.Printf("Unable to elastic IP address, %v\n", err)
+		return
 	}
 
 	if len(result.Addresses) == 0 {
 		fmt.Printf("No elastic IPs for %s region\n", *svc.Config.Region)
 	} else {
-		Tagged, UnTagged := ElasticIpFinder(svc, result.Addresses, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := ElasticIpFinder(svc, result.Addresses, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
@@ -632,31 +936,20 @@ func exitErrorf(msg string, args ...interface{}) {
 	os.Exit(1)
 }
 
-func AmiFinder(svc *ec2.EC2, AmiList []*ec2.Image, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("Workspaces:")
-	var AmiTagList []string
-	var TaggedAmi, UnTaggedAmi []string
+func AmiFinder(svc *ec2.EC2, AmiList []*ec2.Image, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, ami := range AmiList {
-		for index, _ := range ami.Tags {
-			AmiTagList = append(AmiTagList, *ami.Tags[index].Key)
-		}
-		fmt.Println("-->", AmiTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(AmiTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedAmi = append(UnTaggedAmi, *ami.Name)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedAmi = append(TaggedAmi, *ami.Name)
-			}
-		}
-		// ElasticIpTagList = nil
+		Resources = append(Resources, Resource{
+			Type:      "ec2-image",
+			ID:        *ami.ImageId,
+			Tags:      GetEc2Tags(ami.Tags),
+			CreatedAt: ParseTime(aws.StringValue(ami.CreationDate)),
+			Attributes: map[string]string{
+				"Name": aws.StringValue(ami.Name),
+			},
+		})
 	}
-	return TaggedAmi, UnTaggedAmi
+	return ResourceFinder(Resources, Rule)
 }
 
 func AmiInit(PolicyObject *Policy, sess *session.Session) {
@@ -669,43 +962,29 @@ func AmiInit(PolicyObject *Policy, sess *session.Session) {
 	}
 	result, err := svc.DescribeImages(&input)
 	if err != nil {
-		fmt.Print("Unable to load AMI %v", err)
+		fmt.Printf("Unable to load AMI %v\n", err)
+		return
 	}
 
 	if len(result.Images) == 0 {
 		fmt.Printf("No elastic IPs for %s region\n", *svc.Config.Region)
 	} else {
-		Tagged, UnTagged := AmiFinder(svc, result.Images, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := AmiFinder(svc, result.Images, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
 }
 
-func InternetGatewayFinder(svc *ec2.EC2, InternetGatewayList []*ec2.InternetGateway, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("Internet Gateway:")
-	var InternetGatewayTagList []string
-	var TaggedInternetGateway, UnTaggedInternetGateway []string
+func InternetGatewayFinder(svc *ec2.EC2, InternetGatewayList []*ec2.InternetGateway, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, InternetGateway := range InternetGatewayList {
-		for index, _ := range InternetGateway.Tags {
-			InternetGatewayTagList = append(InternetGatewayTagList, *InternetGateway.Tags[index].Key)
-		}
-		fmt.Println("-->", InternetGatewayTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(InternetGatewayTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedInternetGateway = append(UnTaggedInternetGateway, *InternetGateway.InternetGatewayId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedInternetGateway = append(TaggedInternetGateway, *InternetGateway.InternetGatewayId)
-			}
-		}
-		InternetGatewayTagList = nil
+		Resources = append(Resources, Resource{
+			Type: "ec2-internetgateway",
+			ID:   *InternetGateway.InternetGatewayId,
+			Tags: GetEc2Tags(InternetGateway.Tags),
+		})
 	}
-	return TaggedInternetGateway, UnTaggedInternetGateway
+	return ResourceFinder(Resources, Rule)
 }
 
 func InternetGatewayInit(PolicyObject *Policy, sess *session.Session) {
@@ -714,44 +993,37 @@ func InternetGatewayInit(PolicyObject *Policy, sess *session.Session) {
 	input := ec2.DescribeInternetGatewaysInput{}
 	result, err := svc.DescribeInternetGateways(&input)
 	if err != nil {
-		fmt.Print("Unable to load InternetGateway %v", err)
+		fmt.Printf("Unable to load InternetGateway %v\n", err)
+		return
 	}
 
 	if len(result.InternetGateways) == 0 {
 		fmt.Printf("No InternetGateway for %s region\n", *svc.Config.Region)
 	} else {
 		fmt.Println(result.InternetGateways)
-		Tagged, UnTagged := InternetGatewayFinder(svc, result.InternetGateways, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := InternetGatewayFinder(svc, result.InternetGateways, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
 }
 
-func NatGatewayFinder(svc *ec2.EC2, NatGatewayList []*ec2.NatGateway, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("NatGateway:")
-	var NatGatewayTagList []string
-	var TaggedNatGateway, UnTaggedNatGateway []string
+func NatGatewayFinder(svc *ec2.EC2, NatGatewayList []*ec2.NatGateway, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, NatGateway := range NatGatewayList {
-		for index, _ := range NatGateway.Tags {
-			NatGatewayTagList = append(NatGatewayTagList, *NatGateway.Tags[index].Key)
-		}
-		fmt.Println("-->", NatGatewayTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(NatGatewayTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedNatGateway = append(UnTaggedNatGateway, *NatGateway.NatGatewayId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedNatGateway = append(TaggedNatGateway, *NatGateway.NatGatewayId)
-			}
-		}
-		NatGatewayTagList = nil
+		Resources = append(Resources, Resource{
+			Type:      "ec2-natgateway",
+			ID:        *NatGateway.NatGatewayId,
+			Tags:      GetEc2Tags(NatGateway.Tags),
+			CreatedAt: aws.TimeValue(NatGateway.CreateTime),
+			Attributes: map[string]string{
+				"VpcId":    aws.StringValue(NatGateway.VpcId),
+				"SubnetId": aws.StringValue(NatGateway.SubnetId),
+			},
+			Parents: []ParentResource{{Type: "ec2-vpc", ID: aws.StringValue(NatGateway.VpcId)}},
+		})
 	}
-	return TaggedNatGateway, UnTaggedNatGateway
+	ResolveEC2Parents(svc, Resources, Rule)
+	return ResourceFinder(Resources, Rule)
 }
 
 func NatGatewayInit(PolicyObject *Policy, sess *session.Session) {
@@ -759,44 +1031,33 @@ func NatGatewayInit(PolicyObject *Policy, sess *session.Session) {
 	input := ec2.DescribeNatGatewaysInput{}
 	result, err := svc.DescribeNatGateways(&input)
 	if err != nil {
-		fmt.Print("Unable to load NatGateways %v", err)
+		fmt.Printf("Unable to load NatGateways %v\n", err)
+		return
 	}
 
 	if len(result.NatGateways) == 0 {
 		fmt.Printf("No NatGateways for %s region\n", *svc.Config.Region)
 	} else {
 		fmt.Println(result.NatGateways)
-		Tagged, UnTagged := NatGatewayFinder(svc, result.NatGateways, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := NatGatewayFinder(svc, result.NatGateways, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
 }
 
-func NetworkAclFinder(svc *ec2.EC2, NetworkAclList []*ec2.NetworkAcl, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("NetworkAcl:")
-	var NetworkAclTagList []string
-	var TaggedNetworkAcl, UnTaggedNetworkAcl []string
+func NetworkAclFinder(svc *ec2.EC2, NetworkAclList []*ec2.NetworkAcl, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, NetworkAcl := range NetworkAclList {
-		for index, _ := range NetworkAcl.Tags {
-			NetworkAclTagList = append(NetworkAclTagList, *NetworkAcl.Tags[index].Key)
-		}
-		fmt.Println("-->", NetworkAclTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(NetworkAclTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedNetworkAcl = append(UnTaggedNetworkAcl, *NetworkAcl.NetworkAclId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedNetworkAcl = append(TaggedNetworkAcl, *NetworkAcl.NetworkAclId)
-			}
-		}
-		NetworkAclTagList = nil
+		Resources = append(Resources, Resource{
+			Type: "ec2-networkacl",
+			ID:   *NetworkAcl.NetworkAclId,
+			Tags: GetEc2Tags(NetworkAcl.Tags),
+			Attributes: map[string]string{
+				"VpcId": aws.StringValue(NetworkAcl.VpcId),
+			},
+		})
 	}
-	return TaggedNetworkAcl, UnTaggedNetworkAcl
+	return ResourceFinder(Resources, Rule)
 }
 
 func NetworkAclInit(PolicyObject *Policy, sess *session.Session) {
@@ -804,44 +1065,34 @@ func NetworkAclInit(PolicyObject *Policy, sess *session.Session) {
 	input := ec2.DescribeNetworkAclsInput{}
 	result, err := svc.DescribeNetworkAcls(&input)
 	if err != nil {
-		fmt.Print("Unable to load NACL %v", err)
+		fmt.Printf("Unable to load NACL %v\n", err)
+		return
 	}
 
 	if len(result.NetworkAcls) == 0 {
 		fmt.Printf("No NetworkAcls for %s region\n", *svc.Config.Region)
 	} else {
 		fmt.Println(result.NetworkAcls)
-		Tagged, UnTagged := NetworkAclFinder(svc, result.NetworkAcls, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := NetworkAclFinder(svc, result.NetworkAcls, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
 }
 
-func ReservedInstanceFinder(svc *ec2.EC2, ReservedInstanceList []*ec2.ReservedInstances, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("ReservedInstance:")
-	var ReservedInstanceTagList []string
-	var TaggedReservedInstance, UnTaggedReservedInstance []string
+func ReservedInstanceFinder(svc *ec2.EC2, ReservedInstanceList []*ec2.ReservedInstances, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, ReservedInstance := range ReservedInstanceList {
-		for index, _ := range ReservedInstance.Tags {
-			ReservedInstanceTagList = append(ReservedInstanceTagList, *ReservedInstance.Tags[index].Key)
-		}
-		fmt.Println("-->", ReservedInstanceTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(ReservedInstanceTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedReservedInstance = append(UnTaggedReservedInstance, *ReservedInstance.ReservedInstancesId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedReservedInstance = append(TaggedReservedInstance, *ReservedInstance.ReservedInstancesId)
-			}
-		}
-		ReservedInstanceTagList = nil
+		Resources = append(Resources, Resource{
+			Type:      "ec2-reservedinstances",
+			ID:        *ReservedInstance.ReservedInstancesId,
+			Tags:      GetEc2Tags(ReservedInstance.Tags),
+			CreatedAt: aws.TimeValue(ReservedInstance.Start),
+			Attributes: map[string]string{
+				"InstanceType": aws.StringValue(ReservedInstance.InstanceType),
+			},
+		})
 	}
-	return TaggedReservedInstance, UnTaggedReservedInstance
+	return ResourceFinder(Resources, Rule)
 }
 
 func ReservedInstanceInit(PolicyObject *Policy, sess *session.Session) {
@@ -849,13 +1100,14 @@ func ReservedInstanceInit(PolicyObject *Policy, sess *session.Session) {
 	input := ec2.DescribeReservedInstancesInput{}
 	result, err := svc.DescribeReservedInstances(&input)
 	if err != nil {
-		fmt.Print("Unable to load Reserved Instances %v", err)
+		fmt.Printf("Unable to load Reserved Instances %v\n", err)
+		return
 	}
 
 	if len(result.ReservedInstances) == 0 {
 		fmt.Printf("No Reserved Instances for %s region\n", *svc.Config.Region)
 	} else {
-		Tagged, UnTagged := ReservedInstanceFinder(svc, result.ReservedInstances, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := ReservedInstanceFinder(svc, result.ReservedInstances, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
@@ -866,46 +1118,35 @@ func RouteTableInit(PolicyObject *Policy, sess *session.Session) {
 	input := ec2.DescribeRouteTablesInput{}
 	result, err := svc.DescribeRouteTables(&input)
 	if err != nil {
-		fmt.Print("Unable to load Reserved Instances %v", err)
+		fmt.Printf("Unable to load Route Tables %v\n", err)
+		return
 	}
 
 	if len(result.RouteTables) == 0 {
 		fmt.Printf("No Reserved Instances for %s region\n", *svc.Config.Region)
 	} else {
-		Tagged, UnTagged := RouteTableFinder(svc, result.RouteTables, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := RouteTableFinder(svc, result.RouteTables, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
 }
 
-func RouteTableFinder(svc *ec2.EC2, RouteTableList []*ec2.RouteTable, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("Route Table:")
-	var RouteTableTagList []string
-	var TaggedRouteTable, UnTaggedRouteTable []string
+func RouteTableFinder(svc *ec2.EC2, RouteTableList []*ec2.RouteTable, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, RouteTable := range RouteTableList {
-		for index, _ := range RouteTable.Tags {
-			RouteTableTagList = append(RouteTableTagList, *RouteTable.Tags[index].Key)
-		}
-		fmt.Println("-->", RouteTableTagList)
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(RouteTableTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedRouteTable = append(UnTaggedRouteTable, *RouteTable.RouteTableId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedRouteTable = append(TaggedRouteTable, *RouteTable.RouteTableId)
-			}
-		}
-		RouteTableTagList = nil
+		Resources = append(Resources, Resource{
+			Type: "ec2-routetable",
+			ID:   *RouteTable.RouteTableId,
+			Tags: GetEc2Tags(RouteTable.Tags),
+			Attributes: map[string]string{
+				"VpcId": aws.StringValue(RouteTable.VpcId),
+			},
+		})
 	}
-	return TaggedRouteTable, UnTaggedRouteTable
+	return ResourceFinder(Resources, Rule)
 }
 
-// func SecurityGroupFinder(svc *ec2.EC2, SecurityGroupList []*ec2.SecurityGroupReference, PolicyTagList []string) ([]string, []string) {
+// func SecurityGroupFinder(svc *ec2.EC2, SecurityGroupList []*ec2.SecurityGroupReference, Rule PolicyRule) ([]string, []string) {
 // 	TagCheck := true
 // 	fmt.Println("SecurityGroup:")
 // 	var SecurityGroupTagList []string
@@ -943,103 +1184,163 @@ func SecurityGroupReferenceInit(PolicyObject *Policy, sess *session.Session, Sec
 		}
 		result, err := svc.DescribeSecurityGroupReferences(&input)
 		if err != nil {
-			fmt.Print("Unable to load SecurityGroup %v", err)
+			fmt.Printf("Unable to load SecurityGroup %v\n", err)
+			continue
 		}
 		if len(result.SecurityGroupReferenceSet) == 0 {
 			fmt.Printf("No Reserved Instances for %s region\n", *svc.Config.Region)
 		} else {
 			fmt.Println(result.SecurityGroupReferenceSet)
-			// Tagged, UnTagged := RouteTableFinder(svc, result.SecurityGroupReferenceSet, GetPolicyKeys(PolicyObject))
+			// Tagged, UnTagged := RouteTableFinder(svc, result.SecurityGroupReferenceSet, GetPolicyRule(PolicyObject))
 			// fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 			// fmt.Println("Final UnTagged:", UnTagged)
 		}
 	}
 }
 
-func SecurityGroupFinder(svc *ec2.EC2, SnapshotList []*ec2.Snapshot, PolicyTagList []string) ([]string, []string) {
-	TagCheck := true
-	fmt.Println("Snapshot:")
-	var SnapshotTagList []string
-	var TaggedSnapshot, UnTaggedSnapshot []string
+func SecurityGroupFinder(svc *ec2.EC2, SnapshotList []*ec2.Snapshot, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
 	for _, Snapshot := range SnapshotList {
-		for index, _ := range Snapshot.Tags {
-			SnapshotTagList = append(SnapshotTagList, *Snapshot.Tags[index].Key)
+		Resource := Resource{
+			Type:      "ec2-snapshot",
+			ID:        *Snapshot.SnapshotId,
+			Tags:      GetEc2Tags(Snapshot.Tags),
+			CreatedAt: aws.TimeValue(Snapshot.StartTime),
+			Attributes: map[string]string{
+				"VolumeId": aws.StringValue(Snapshot.VolumeId),
+			},
 		}
-		for index, PolicyTag := range PolicyTagList {
-			fmt.Println("Policy Tag: ", index, " ", PolicyTag)
-			TagCheck = contains(SnapshotTagList, PolicyTag)
-			if TagCheck == false {
-				fmt.Println("TagCheck Failed.")
-				UnTaggedSnapshot = append(UnTaggedSnapshot, *Snapshot.DataEncryptionKeyId)
-				break
-			} else if len(PolicyTagList)-1 == index && TagCheck == true {
-				fmt.Println("TagCheck Success.")
-				TaggedSnapshot = append(TaggedSnapshot, *Snapshot.DataEncryptionKeyId)
-			}
+		// Copied snapshots carry the placeholder volume vol-ffffffff.
+		if VolumeId := aws.StringValue(Snapshot.VolumeId); VolumeId != "" && VolumeId != "vol-ffffffff" {
+			Resource.Parents = []ParentResource{{Type: "ec2-volume", ID: VolumeId}}
 		}
-		SnapshotTagList = nil
+		Resources = append(Resources, Resource)
+	}
+	ResolveEC2Parents(svc, Resources, Rule)
+	return ResourceFinder(Resources, Rule)
+}
+
+func Ec2SecurityGroupFinder(svc *ec2.EC2, SecurityGroupList []*ec2.SecurityGroup, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, SecurityGroup := range SecurityGroupList {
+		Resources = append(Resources, Resource{
+			Type: "ec2-securitygroup",
+			ID:   *SecurityGroup.GroupId,
+			Tags: GetEc2Tags(SecurityGroup.Tags),
+			Attributes: map[string]string{
+				"GroupName": aws.StringValue(SecurityGroup.GroupName),
+				"VpcId":     aws.StringValue(SecurityGroup.VpcId),
+			},
+		})
 	}
-	return TaggedSnapshot, UnTaggedSnapshot
+	return ResourceFinder(Resources, Rule)
 }
 
 func SecurityGroupInit(PolicyObject *Policy, sess *session.Session) {
 	svc := ec2.New(sess)
-	input := ec2.DescribeSecurityGroupRulesInput{}
-	result, err := svc.DescribeSecurityGroupRules(&input)
+	var SecurityGroups []*ec2.SecurityGroup
+	err := svc.DescribeSecurityGroupsPages(&ec2.DescribeSecurityGroupsInput{}, func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
+		SecurityGroups = append(SecurityGroups, page.SecurityGroups...)
+		return true
+	})
 	if err != nil {
-		fmt.Print("Unable to load SecurityGroupRules %v", err)
+		fmt.Printf("Unable to load Security Groups %v\n", err)
+		return
 	}
-	if len(result.SecurityGroupRules) == 0 {
+	if len(SecurityGroups) == 0 {
 		fmt.Printf("No Security Group for %s region\n", *svc.Config.Region)
 	} else {
-		// Tagged, UnTagged := SecurityGroupFinder(svc, result.SecurityGroupRules, GetPolicyKeys(PolicyObject))
-		// fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
-		// fmt.Println("Final UnTagged:", UnTagged)
+		Tagged, UnTagged := Ec2SecurityGroupFinder(svc, SecurityGroups, GetPolicyRule(PolicyObject))
+		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+		fmt.Println("Final UnTagged:", UnTagged)
 	}
 }
 
 func EC2SnapShotInit(PolicyObject *Policy, sess *session.Session) {
 	svc := ec2.New(sess)
-	input := ec2.DescribeSnapshotsInput{}
-	result, err := svc.DescribeSnapshots(&input)
+	// Without an owner DescribeSnapshots also returns every public snapshot.
+	input := ec2.DescribeSnapshotsInput{
+		OwnerIds: aws.StringSlice([]string{"self"}),
+	}
+	var Snapshots []*ec2.Snapshot
+	err := svc.DescribeSnapshotsPages(&input, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		Snapshots = append(Snapshots, page.Snapshots...)
+		return true
+	})
 	if err != nil {
-		fmt.Print("Unable to load SnapShot %v", err)
+		fmt.Printf("Unable to load SnapShot %v\n", err)
+		return
 	}
-	if len(result.Snapshots) == 0 {
+	if len(Snapshots) == 0 {
 		fmt.Printf("No Snapshots for %s region\n", *svc.Config.Region)
 	} else {
-		Tagged, UnTagged := SecurityGroupFinder(svc, result.Snapshots, GetPolicyKeys(PolicyObject))
+		Tagged, UnTagged := SecurityGroupFinder(svc, Snapshots, GetPolicyRule(PolicyObject))
 		fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
 		fmt.Println("Final UnTagged:", UnTagged)
 	}
 }
 
-func main() {
-	VarCheck()
-
-	PolicyObject := GetPolicyData("policy.yaml")
+func NewSession() *session.Session {
 	conf := aws.Config{Region: aws.String(AWS_REGION)}
 
 	sess, err := session.NewSession(&conf)
 	if err != nil {
 		fmt.Println(err)
 	}
-	// S3Init(PolicyObject,sess)
-	// EC2Init(PolicyObject, sess)
-	// ELBInit(PolicyObject, sess)
-	// ElbTargetGroupInit(PolicyObject, sess)
-	// LambdaInit(PolicyObject, sess)
-	// RDSInit(PolicyObject, sess)
-	// Route53Init(PolicyObject, sess)
-	// SQSInit(PolicyObject, sess)
-	// WorkspacesInit(PolicyObject, sess)
-	// ElasticIpInit(PolicyObject, sess)
-	// AmiInit(PolicyObject, sess)
-	// InternetGatewayInit(PolicyObject, sess)
-	// NatGatewayInit(PolicyObject, sess)
-	// NetworkAclInit(PolicyObject, sess)
-	// ReservedInstanceInit(PolicyObject, sess)
-	// RouteTableInit(PolicyObject, sess)
-	// SecurityGroupInit(PolicyObject, sess)
-	EC2SnapShotInit(PolicyObject, sess)
+	return sess
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "policy" {
+		PolicyCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "remediate" {
+		RemediateCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "quarantine" {
+		QuarantineCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rollback" {
+		RollbackCommand(os.Args[2:])
+		return
+	}
+	VarCheck()
+
+	PolicyObject := GetPolicyData("policy.yaml")
+	// Enforced violations of at least this severity fail the run.
+	FailSeverity := os.Getenv("TAG_POLICE_FAIL_SEVERITY")
+	if FailSeverity == "" {
+		FailSeverity = SeverityInfo
+	}
+	if SeverityRank(FailSeverity) == -1 {
+		log.Fatalf("TAG_POLICE_FAIL_SEVERITY: unknown severity %q", FailSeverity)
+	}
+	// Violations of at least this severity are posted to TAG_POLICE_NOTIFY_URL.
+	NotifySeverity := os.Getenv("TAG_POLICE_NOTIFY_SEVERITY")
+	if NotifySeverity == "" {
+		NotifySeverity = DefaultNotifySeverity
+	}
+	if SeverityRank(NotifySeverity) == -1 {
+		log.Fatalf("TAG_POLICE_NOTIFY_SEVERITY: unknown severity %q", NotifySeverity)
+	}
+	ExemptionList = GetExemptionData("exemptions.yaml")
+	sess := NewSession()
+	RunPolicy(PolicyObject, sess)
+	// Attribute violations to their creator: "lookup" uses CloudTrail
+	// LookupEvents, anything else is a log archive directory or s3:// URL.
+	if Creators := os.Getenv("TAG_POLICE_CREATORS"); Creators != "" {
+		AttributeCreators(sess, Creators)
+	}
+	PrintReport()
+	if NotifyURL := os.Getenv("TAG_POLICE_NOTIFY_URL"); NotifyURL != "" {
+		if Notified := GetNotifyFindings(Report, NotifySeverity); len(Notified) > 0 {
+			if err := Notify(NotifyURL, GetNotificationText(Notified, NotifySeverity)); err != nil {
+				fmt.Printf("Unable to send notification %v\n", err)
+			}
+		}
+	}
+	os.Exit(GetExitCode(FailSeverity))
 }
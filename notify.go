@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultNotifySeverity is the lowest severity notified when
+// TAG_POLICE_NOTIFY_SEVERITY isn't set.
+const DefaultNotifySeverity = SeverityHigh
+
+// NotifyLimit caps the violations listed in a notification, the rest are
+// counted.
+const NotifyLimit = 20
+
+// GetNotifyFindings returns the violations of policies that aren't in audit
+// mode with at least NotifySeverity, most severe first.
+func GetNotifyFindings(Findings []Finding, NotifySeverity string) []Finding {
+	var Notified []Finding
+	for _, Finding := range Findings {
+		if Finding.Status == StatusViolation && Finding.Mode != ModeAudit && SeverityRank(Finding.Severity) >= SeverityRank(NotifySeverity) {
+			Notified = append(Notified, Finding)
+		}
+	}
+	sort.SliceStable(Notified, func(i, j int) bool {
+		return SeverityRank(Notified[i].Severity) > SeverityRank(Notified[j].Severity)
+	})
+	return Notified
+}
+
+// GetNotificationText summarizes the violations, one line each.
+func GetNotificationText(Findings []Finding, NotifySeverity string) string {
+	Lines := []string{fmt.Sprintf("tag-police: %d violations of severity %s or higher", len(Findings), NotifySeverity)}
+	for index, Finding := range Findings {
+		if index == NotifyLimit {
+			Lines = append(Lines, fmt.Sprintf("and %d more", len(Findings)-NotifyLimit))
+			break
+		}
+		Line := fmt.Sprintf("- [%s] %s [%s] %s %s", Finding.Severity, Finding.Policy, Finding.Resource.Region, Finding.Resource.Type, Finding.Resource.ID)
+		if len(Finding.Missing) > 0 {
+			Line += " missing: " + strings.Join(Finding.Missing, ", ")
+		}
+		if len(Finding.Invalid) > 0 {
+			Line += " invalid: " + strings.Join(Finding.Invalid, ", ")
+		}
+		if len(Finding.Failed) > 0 {
+			Line += " failed: " + strings.Join(Finding.Failed, "; ")
+		}
+		Lines = append(Lines, Line)
+	}
+	return strings.Join(Lines, "\n")
+}
+
+// Notify posts the text to an incoming webhook, as {"text": ...} like Slack
+// and compatible chat services expect.
+func Notify(URL string, Text string) error {
+	Body, err := json.Marshal(map[string]string{"text": Text})
+	if err != nil {
+		return err
+	}
+	Client := http.Client{Timeout: 30 * time.Second}
+	Response, err := Client.Post(URL, "application/json", bytes.NewReader(Body))
+	if err != nil {
+		return err
+	}
+	defer Response.Body.Close()
+	if Response.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", Response.Status)
+	}
+	return nil
+}
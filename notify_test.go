@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetNotifyFindings(t *testing.T) {
+	Findings := []Finding{
+		{Policy: "medium", Resource: Resource{ID: "i-1"}, Status: StatusViolation, Severity: SeverityMedium, Mode: ModeWarn},
+		{Policy: "high", Resource: Resource{ID: "i-2"}, Status: StatusViolation, Severity: SeverityHigh, Mode: ModeEnforce},
+		{Policy: "audit", Resource: Resource{ID: "i-3"}, Status: StatusViolation, Severity: SeverityCritical, Mode: ModeAudit},
+		{Policy: "exempt", Resource: Resource{ID: "i-4"}, Status: StatusExempt, Severity: SeverityCritical, Mode: ModeEnforce},
+		{Policy: "critical", Resource: Resource{ID: "i-5"}, Status: StatusViolation, Severity: SeverityCritical, Mode: ModeWarn},
+		{Policy: "high", Resource: Resource{ID: "i-6"}, Status: StatusViolation, Severity: SeverityHigh, Mode: ModeWarn},
+		{Policy: "pending", Resource: Resource{ID: "i-7"}, Status: StatusPending, Severity: SeverityHigh, Mode: ModeWarn},
+	}
+	Cases := []struct {
+		NotifySeverity string
+		IDs            []string
+	}{
+		{SeverityCritical, []string{"i-5"}},
+		{SeverityHigh, []string{"i-5", "i-2", "i-6"}},
+		{SeverityInfo, []string{"i-5", "i-2", "i-6", "i-1"}},
+	}
+	for _, Case := range Cases {
+		var IDs []string
+		for _, Finding := range GetNotifyFindings(Findings, Case.NotifySeverity) {
+			IDs = append(IDs, Finding.Resource.ID)
+		}
+		if !reflect.DeepEqual(IDs, Case.IDs) {
+			t.Errorf("%s: got %v, want %v", Case.NotifySeverity, IDs, Case.IDs)
+		}
+	}
+}
+
+func TestGetNotificationText(t *testing.T) {
+	Violation := Finding{Policy: "default", Resource: Resource{Type: "ec2", ID: "i-1", Region: "eu-west-1"}, Status: StatusViolation, Missing: []string{"Team", "Owner"}, Severity: SeverityHigh, Mode: ModeWarn}
+	Text := GetNotificationText([]Finding{Violation}, SeverityHigh)
+	Want := "tag-police: 1 violations of severity high or higher\n- [high] default [eu-west-1] ec2 i-1 missing: Team, Owner"
+	if Text != Want {
+		t.Errorf("got %q, want %q", Text, Want)
+	}
+
+	for _, Count := range []int{NotifyLimit, NotifyLimit + 5} {
+		var Findings []Finding
+		for index := 0; index < Count; index++ {
+			Violation.Resource.ID = fmt.Sprintf("i-%d", index)
+			Findings = append(Findings, Violation)
+		}
+		Lines := strings.Split(GetNotificationText(Findings, SeverityHigh), "\n")
+		if Count <= NotifyLimit {
+			if len(Lines) != Count+1 {
+				t.Errorf("%d findings: got %d lines, want %d", Count, len(Lines), Count+1)
+			}
+			continue
+		}
+		if len(Lines) != NotifyLimit+2 {
+			t.Errorf("%d findings: got %d lines, want %d", Count, len(Lines), NotifyLimit+2)
+		}
+		if Last := Lines[len(Lines)-1]; Last != "and 5 more" {
+			t.Errorf("%d findings: got last line %q, want %q", Count, Last, "and 5 more")
+		}
+	}
+}
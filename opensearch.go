@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/opensearchservice"
+)
+
+// OpenSearchDescribeDomainsLimit is the maximum number of domains accepted by
+// a single DescribeDomains call.
+const OpenSearchDescribeDomainsLimit = 5
+
+func OpenSearchFinder(svc *opensearchservice.OpenSearchService, DomainNames []*string, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for start := 0; start < len(DomainNames); start += OpenSearchDescribeDomainsLimit {
+		end := start + OpenSearchDescribeDomainsLimit
+		if end > len(DomainNames) {
+			end = len(DomainNames)
+		}
+		result, err := svc.DescribeDomains(&opensearchservice.DescribeDomainsInput{
+			DomainNames: DomainNames[start:end],
+		})
+		if err != nil {
+			fmt.Printf("Unable to describe OpenSearch Domains %v\n", err)
+			continue
+		}
+		for _, Domain := range result.DomainStatusList {
+			TagObject, err := svc.ListTags(&opensearchservice.ListTagsInput{
+				ARN: Domain.ARN,
+			})
+			if err != nil {
+				fmt.Printf("Unable to load tags for %s %v\n", *Domain.DomainName, err)
+				continue
+			}
+			Tags := make(map[string]string)
+			for _, Tag := range TagObject.TagList {
+				Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+			}
+			Resources = append(Resources, Resource{
+				Type: "opensearch-domain",
+				ID:   *Domain.DomainName,
+				Arn:  aws.StringValue(Domain.ARN),
+				Tags: Tags,
+			})
+		}
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func OpenSearchInit(PolicyObject *Policy, sess *session.Session) {
+	svc := opensearchservice.New(sess)
+	// ListDomainNames returns every domain in a single response.
+	result, err := svc.ListDomainNames(&opensearchservice.ListDomainNamesInput{})
+	if err != nil {
+		fmt.Printf("Unable to load OpenSearch Domains %v\n", err)
+		return
+	}
+	var DomainNames []*string
+	for _, Domain := range result.DomainNames {
+		DomainNames = append(DomainNames, Domain.DomainName)
+	}
+	if len(DomainNames) == 0 {
+		fmt.Printf("No OpenSearch Domains for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := OpenSearchFinder(svc, DomainNames, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
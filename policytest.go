@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SkippedOutcome is the outcome of a test case no policy checks, because no
+// policy lists its resource type or the selectors skip it.
+const SkippedOutcome = "skipped"
+
+// PolicyTests is a policy test fixture file. Each case is a synthetic
+// resource and the outcome the policy should give it:
+//
+//	now: 2026-01-31T00:00:00Z
+//	cases:
+//	- name: production bucket without classification
+//	  policy: production
+//	  resource:
+//	    type: s3
+//	    id: prod-invoices
+//	    tags:
+//	      Team: billing
+//	      Environment: prod
+//	  expect: violation
+//	  missing: ["DataClassification"]
+type PolicyTests struct {
+	Now   string       `yaml:"now"`
+	Cases []PolicyTest `yaml:"cases"`
+}
+
+// PolicyTest is one test case. Policy limits the case to one policy,
+// otherwise every policy listing the resource type must give the expected
+// outcome. Missing, when set, must be exactly the missing keys, and Renames
+// the suggested renames from alias to key.
+type PolicyTest struct {
+	Name     string            `yaml:"name"`
+	Policy   string            `yaml:"policy"`
+	Resource FixtureResource   `yaml:"resource"`
+	Expect   string            `yaml:"expect"`
+	Missing  []string          `yaml:"missing"`
+	Renames  map[string]string `yaml:"renames"`
+}
+
+type FixtureResource struct {
+	Type       string            `yaml:"type"`
+	ID         string            `yaml:"id"`
+	Arn        string            `yaml:"arn"`
+	Region     string            `yaml:"region"`
+	Account    string            `yaml:"account"`
+	Tags       map[string]string `yaml:"tags"`
+	Attributes map[string]string `yaml:"attributes"`
+	CreatedAt  string            `yaml:"createdat"`
+	Parents    []FixtureParent   `yaml:"parents"`
+}
+
+type FixtureParent struct {
+	Type string            `yaml:"type"`
+	ID   string            `yaml:"id"`
+	Tags map[string]string `yaml:"tags"`
+}
+
+func (Fixture FixtureResource) Resource() Resource {
+	Resource := Resource{
+		Type:       Fixture.Type,
+		ID:         Fixture.ID,
+		Arn:        Fixture.Arn,
+		Region:     Fixture.Region,
+		Account:    Fixture.Account,
+		Tags:       Fixture.Tags,
+		Attributes: Fixture.Attributes,
+		CreatedAt:  ParseTime(Fixture.CreatedAt),
+	}
+	for _, Parent := range Fixture.Parents {
+		Resource.Parents = append(Resource.Parents, ParentResource(Parent))
+	}
+	return Resource
+}
+
+func GetPolicyTests(filePath string) PolicyTests {
+	yamlFile, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var data PolicyTests
+	if err := yaml.UnmarshalStrict(yamlFile, &data); err != nil {
+		log.Fatalf("%s: %v", filePath, err)
+	}
+	if data.Now != "" && ParseTime(data.Now).IsZero() {
+		log.Fatalf("%s: now: invalid time %q", filePath, data.Now)
+	}
+	for index, Test := range data.Cases {
+		if Test.Resource.Type == "" {
+			log.Fatalf("%s: case %d: resource type is empty", filePath, index+1)
+		}
+		if Test.Resource.CreatedAt != "" && ParseTime(Test.Resource.CreatedAt).IsZero() {
+			log.Fatalf("%s: case %d: createdat: invalid time %q", filePath, index+1, Test.Resource.CreatedAt)
+		}
+		switch Test.Expect {
+		case StatusCompliant, StatusViolation, StatusExempt, StatusSuppressed, StatusPending, SkippedOutcome:
+		default:
+			log.Fatalf("%s: case %d: unknown expect %q", filePath, index+1, Test.Expect)
+		}
+	}
+	return data
+}
+
+// RunPolicyTest evaluates a test case against every policy it applies to,
+// with the same EvaluateResource the scanners use, and returns why it failed.
+func RunPolicyTest(PolicyObject *Policy, Test PolicyTest, Now time.Time) []string {
+	var Failures []string
+	Resource := Test.Resource.Resource()
+	Checked := false
+	for _, Rule := range PolicyObject.Policy {
+		if Test.Policy != "" && Rule.Name != Test.Policy {
+			continue
+		}
+		if !ContainsString(Rule.Resources, Resource.Type) {
+			continue
+		}
+		Finding, Selected := EvaluateResource(Rule, Resource, Now)
+		if !Selected {
+			continue
+		}
+		Checked = true
+		if Finding.Status != Test.Expect {
+			Failures = append(Failures, fmt.Sprintf("policy %s: expected %s, got %s %s", Rule.Name, Test.Expect, Finding.Status, DescribeFinding(Finding)))
+			continue
+		}
+		if Test.Missing != nil && !SameStrings(Test.Missing, Finding.Missing) {
+			Failures = append(Failures, fmt.Sprintf("policy %s: expected missing %v, got %v", Rule.Name, Test.Missing, Finding.Missing))
+		}
+		if Test.Renames != nil {
+			Renames := make(map[string]string)
+			for _, Rename := range Finding.Renames {
+				Renames[Rename.From] = Rename.To
+			}
+			if !reflect.DeepEqual(Renames, Test.Renames) {
+				Failures = append(Failures, fmt.Sprintf("policy %s: expected renames %v, got %v", Rule.Name, Test.Renames, Renames))
+			}
+		}
+	}
+	if !Checked && Test.Expect != SkippedOutcome {
+		Failures = append(Failures, fmt.Sprintf("expected %s, got %s", Test.Expect, SkippedOutcome))
+	}
+	if Checked && Test.Expect == SkippedOutcome {
+		Failures = append(Failures, "expected "+SkippedOutcome+", but a policy checked the resource")
+	}
+	return Failures
+}
+
+func DescribeFinding(Finding Finding) string {
+	var Details []string
+	if len(Finding.Missing) > 0 {
+		Details = append(Details, "missing: "+strings.Join(Finding.Missing, ", "))
+	}
+	if len(Finding.Invalid) > 0 {
+		Details = append(Details, "invalid: "+strings.Join(Finding.Invalid, ", "))
+	}
+	if len(Finding.Failed) > 0 {
+		Details = append(Details, "failed: "+strings.Join(Finding.Failed, "; "))
+	}
+	return strings.Join(Details, " ")
+}
+
+func SameStrings(a, b []string) bool {
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return strings.Join(a, "\x00") == strings.Join(b, "\x00")
+}
+
+// PolicyTestCommand runs every test case offline and returns the number of
+// failed cases.
+func PolicyTestCommand(PolicyFile, TestFile string) int {
+	PolicyObject := GetPolicyData(PolicyFile)
+	Tests := GetPolicyTests(TestFile)
+	Now := time.Now()
+	if Tests.Now != "" {
+		Now = ParseTime(Tests.Now)
+	}
+	Failed := 0
+	for index, Test := range Tests.Cases {
+		Name := Test.Name
+		if Name == "" {
+			Name = fmt.Sprintf("case %d", index+1)
+		}
+		Failures := RunPolicyTest(PolicyObject, Test, Now)
+		if len(Failures) == 0 {
+			fmt.Println("PASS", Name)
+			continue
+		}
+		Failed++
+		fmt.Println("FAIL", Name)
+		for _, Failure := range Failures {
+			fmt.Println("    " + Failure)
+		}
+	}
+	fmt.Printf("\n%d passed, %d failed\n", len(Tests.Cases)-Failed, Failed)
+	return Failed
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPolicyTestFile runs the shipped policy.test.yaml against policy.yaml,
+// as tag-police policy test does.
+func TestPolicyTestFile(t *testing.T) {
+	PolicyObject := GetPolicyData("policy.yaml")
+	Tests := GetPolicyTests("policy.test.yaml")
+	if len(Tests.Cases) == 0 {
+		t.Fatal("policy.test.yaml has no cases")
+	}
+	for _, Test := range Tests.Cases {
+		if Failures := RunPolicyTest(PolicyObject, Test, TestNow); len(Failures) > 0 {
+			t.Errorf("%s: %v", Test.Name, Failures)
+		}
+	}
+}
+
+func TestRunPolicyTest(t *testing.T) {
+	PolicyObject := &Policy{Policy: []PolicyRule{
+		{Name: "global", Resources: []string{"ec2"}, Keys: []PolicyKey{{Key: "Team", Aliases: []string{"team"}}, {Key: "Owner"}}},
+		{Name: "production", Resources: []string{"ec2"}, Keys: []PolicyKey{{Key: "Owner"}}, Selector: Selector{Include: []ResourceMatch{{Name: "prod-*"}}}},
+	}}
+	Instance := FixtureResource{Type: "ec2", ID: "i-1", Tags: map[string]string{"team": "web"}}
+	Cases := []struct {
+		Name     string
+		Test     PolicyTest
+		Failures int
+	}{
+		{"expected violation", PolicyTest{Resource: Instance, Expect: StatusViolation, Missing: []string{"Owner"}, Renames: map[string]string{"team": "Team"}}, 0},
+		{"wrong outcome", PolicyTest{Resource: Instance, Expect: StatusCompliant}, 1},
+		{"wrong missing keys", PolicyTest{Resource: Instance, Expect: StatusViolation, Missing: []string{"Team", "Owner"}}, 1},
+		{"wrong renames", PolicyTest{Resource: Instance, Expect: StatusViolation, Renames: map[string]string{}}, 1},
+		{"every selected policy", PolicyTest{Resource: FixtureResource{Type: "ec2", ID: "prod-1"}, Expect: StatusCompliant}, 2},
+		{"one policy", PolicyTest{Policy: "production", Resource: FixtureResource{Type: "ec2", ID: "prod-1"}, Expect: StatusViolation}, 0},
+		{"skipped", PolicyTest{Resource: FixtureResource{Type: "s3", ID: "logs"}, Expect: SkippedOutcome}, 0},
+		{"not skipped", PolicyTest{Resource: Instance, Expect: SkippedOutcome}, 2},
+		{"created recently, no grace period", PolicyTest{Resource: FixtureResource{Type: "ec2", ID: "i-2", CreatedAt: "2026-01-31T00:00:00Z"}, Expect: StatusViolation}, 0},
+	}
+	for _, Case := range Cases {
+		Failures := RunPolicyTest(PolicyObject, Case.Test, TestNow.Add(time.Hour))
+		if len(Failures) != Case.Failures {
+			t.Errorf("%s: got failures %q, want %d", Case.Name, Failures, Case.Failures)
+		}
+	}
+}
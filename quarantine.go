@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gopkg.in/yaml.v2"
+)
+
+// Quarantine escalates violations of enforce policies that stay unresolved
+// for longer than After. Actions maps a resource identifier to the action
+// taken on its resources:
+//
+//	quarantine:
+//	  after: 14d
+//	  actions:
+//	    ec2: stop
+//	    lambda-functions: disable
+//	    s3: block-public-access
+//	    ec2-volume: delete
+type Quarantine struct {
+	After   string            `yaml:"after,omitempty"`
+	Actions map[string]string `yaml:"actions,omitempty"`
+}
+
+// QuarantineActions maps each action to the resource identifiers it supports.
+// An action returns the resource's state before and after it, for rollback.
+var QuarantineActions = map[string]map[string]func(*session.Session, Resource) (*MutationState, *MutationState, error){
+	"stop":                {"ec2": StopEC2Instance},
+	"disable":             {"lambda-functions": DisableLambdaFunction},
+	"block-public-access": {"s3": BlockS3PublicAccess},
+	"delete":              {"ec2-volume": ScheduleVolumeDeletion},
+}
+
+func GetQuarantineActionNames() []string {
+	var Names []string
+	for Name := range QuarantineActions {
+		Names = append(Names, Name)
+	}
+	sort.Strings(Names)
+	return Names
+}
+
+// ErrQuarantinePending is returned by actions that are scheduled but not due
+// yet, they changed nothing.
+var ErrQuarantinePending = errors.New("scheduled, not due yet")
+
+// VolumeDeletionTag marks a volume scheduled for deletion, the value is the
+// date it is deleted after. VolumeDeletionDelay is the time between the two.
+const (
+	VolumeDeletionTag   = "tag-police:delete-after"
+	VolumeDeletionDelay = 7 * 24 * time.Hour
+)
+
+// Approval allows a quarantine action on the resources matching Resource, an
+// ID or ARN pattern like an exemption's. Without an approval actions are only
+// planned.
+type Approval struct {
+	Resource string `yaml:"resource"`
+	Action   string `yaml:"action"`
+	Approver string `yaml:"approver"`
+	Expires  string `yaml:"expires"`
+}
+
+type Approvals struct {
+	Approvals []Approval `yaml:"approvals"`
+}
+
+func GetApprovalData(filePath string) []Approval {
+	// The approvals file is optional, without it nothing is quarantined.
+	yamlFile, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	var data Approvals
+	if err := yaml.UnmarshalStrict(yamlFile, &data); err != nil {
+		log.Fatalf("%s: %v", filePath, err)
+	}
+	for index, Approval := range data.Approvals {
+		if Approval.Resource == "" || Approval.Action == "" || Approval.Approver == "" {
+			log.Fatalf("%s: approval %d: resource, action and approver are required", filePath, index+1)
+		}
+		if Approval.Expires != "" {
+			if _, err := time.Parse(ExemptionDateLayout, Approval.Expires); err != nil {
+				log.Fatalf("%s: approval %d: expires must be YYYY-MM-DD", filePath, index+1)
+			}
+		}
+	}
+	return data.Approvals
+}
+
+func GetApproval(ApprovalList []Approval, Action string, Resource Resource, Now time.Time) (Approval, bool) {
+	for _, Approval := range ApprovalList {
+		if Approval.Action != Action || DateExpired(Approval.Expires, Now) {
+			continue
+		}
+		if MatchPattern(Approval.Resource, Resource.ID) || (Resource.Arn != "" && MatchPattern(Approval.Resource, Resource.Arn)) {
+			return Approval, true
+		}
+	}
+	return Approval{}, false
+}
+
+// QuarantineState records when each violation was first seen by a quarantine
+// run, keyed by policy, region, resource identifier and ID. It is saved after
+// every run, violations that were scanned and found resolved are dropped.
+type QuarantineState map[string]time.Time
+
+func GetQuarantineState(filePath string) QuarantineState {
+	State := make(QuarantineState)
+	Content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return State
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := json.Unmarshal(Content, &State); err != nil {
+		log.Fatalf("%s: %v", filePath, err)
+	}
+	return State
+}
+
+func (State QuarantineState) Save(filePath string) error {
+	Content, err := json.MarshalIndent(State, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, append(Content, '\n'), 0644)
+}
+
+// QuarantinePlan is one action due on a resource.
+type QuarantinePlan struct {
+	Finding   Finding
+	Action    string
+	FirstSeen time.Time
+	Approval  *Approval
+}
+
+// GetQuarantinePlans updates the state with the violations of enforce
+// policies that have a quarantine action for their resource, and returns the
+// actions of those past their policy's deadline. Entries of resources that
+// weren't scanned, e.g. in another region or after a scanner error, are kept.
+func GetQuarantinePlans(PolicyObject *Policy, Findings []Finding, State QuarantineState, ApprovalList []Approval, Now time.Time) ([]QuarantinePlan, QuarantineState) {
+	Rules := make(map[string]PolicyRule)
+	for _, Rule := range PolicyObject.Policy {
+		Rules[Rule.Name] = Rule
+	}
+	var Plans []QuarantinePlan
+	NewState := make(QuarantineState)
+	for Key, FirstSeen := range State {
+		NewState[Key] = FirstSeen
+	}
+	for _, Finding := range Findings {
+		Rule := Rules[Finding.Policy]
+		Action, ok := Rule.Quarantine.Actions[Finding.Resource.Type]
+		Key := Finding.Policy + "/" + Finding.Resource.Region + "/" + Finding.Resource.Type + "/" + Finding.Resource.ID
+		if Finding.Status != StatusViolation || Finding.Mode != ModeEnforce || !ok {
+			delete(NewState, Key)
+			continue
+		}
+		FirstSeen, ok := State[Key]
+		if !ok {
+			FirstSeen = Now
+		}
+		NewState[Key] = FirstSeen
+		After, _ := ParseGracePeriod(Rule.Quarantine.After)
+		if Now.Sub(FirstSeen) < After {
+			continue
+		}
+		Plan := QuarantinePlan{Finding: Finding, Action: Action, FirstSeen: FirstSeen}
+		if Approval, ok := GetApproval(ApprovalList, Action, Finding.Resource, Now); ok {
+			Plan.Approval = &Approval
+		}
+		Plans = append(Plans, Plan)
+	}
+	return Plans, NewState
+}
+
+func PrintQuarantinePlans(Plans []QuarantinePlan) {
+	fmt.Println("\n\n\n\nQuarantine plan:")
+	for _, Plan := range Plans {
+		fmt.Printf("  - %s %s %s, non-compliant with %s since %s", Plan.Action, Plan.Finding.Resource.Type, Plan.Finding.Resource.ID,
+			Plan.Finding.Policy, Plan.FirstSeen.Format(time.RFC3339))
+		if Plan.Approval != nil {
+			fmt.Printf(", approved by %s\n", Plan.Approval.Approver)
+		} else {
+			fmt.Println(", awaiting approval")
+		}
+	}
+}
+
+// ApplyQuarantinePlans runs the approved actions and returns the number that
+// failed. Every attempt is written to the journal with the resource's state
+// before and after it.
+func ApplyQuarantinePlans(sess *session.Session, Plans []QuarantinePlan, Journal string, Run string) int {
+	Failed := 0
+	for _, Plan := range Plans {
+		if Plan.Approval == nil {
+			continue
+		}
+		Resource := Plan.Finding.Resource
+		Before, After, err := QuarantineActions[Plan.Action][Resource.Type](sess, Resource)
+		if errors.Is(err, ErrQuarantinePending) {
+			fmt.Printf("Pending %s %s: %s %v\n", Resource.Type, Resource.ID, Plan.Action, err)
+			continue
+		}
+		Entry := AuditEntry{
+			Time:     time.Now().UTC(),
+			Run:      Run,
+			Action:   "quarantine:" + Plan.Action,
+			Type:     Resource.Type,
+			ID:       Resource.ID,
+			Arn:      Resource.Arn,
+			Region:   Resource.Region,
+			Approver: Plan.Approval.Approver,
+			Before:   Before,
+			After:    After,
+		}
+		if err != nil {
+			Failed++
+			Entry.Error = err.Error()
+			fmt.Printf("Unable to %s %s %s %v\n", Plan.Action, Resource.Type, Resource.ID, err)
+		} else {
+			fmt.Printf("Quarantined %s %s: %s\n", Resource.Type, Resource.ID, Plan.Action)
+		}
+		if err := WriteAuditEntry(Journal, Entry); err != nil {
+			fmt.Printf("Unable to write journal %s %v\n", Journal, err)
+		}
+	}
+	return Failed
+}
+
+func StopEC2Instance(sess *session.Session, Resource Resource) (*MutationState, *MutationState, error) {
+	result, err := ec2.New(sess).StopInstances(&ec2.StopInstancesInput{
+		InstanceIds: aws.StringSlice([]string{Resource.ID}),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	Before := &MutationState{State: map[string]string{"State": Resource.Attributes["State"]}}
+	for _, Change := range result.StoppingInstances {
+		Before.State["State"] = aws.StringValue(Change.PreviousState.Name)
+	}
+	return Before, &MutationState{State: map[string]string{"State": ec2.InstanceStateNameStopped}}, nil
+}
+
+// DisableLambdaFunction sets the function's reserved concurrency to 0, so
+// none of its triggers can invoke it.
+func DisableLambdaFunction(sess *session.Session, Resource Resource) (*MutationState, *MutationState, error) {
+	svc := lambda.New(sess)
+	result, err := svc.GetFunctionConcurrency(&lambda.GetFunctionConcurrencyInput{
+		FunctionName: aws.String(Resource.ID),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	Before := &MutationState{State: map[string]string{}}
+	if result.ReservedConcurrentExecutions != nil {
+		Before.State["ReservedConcurrency"] = strconv.FormatInt(*result.ReservedConcurrentExecutions, 10)
+	}
+	_, err = svc.PutFunctionConcurrency(&lambda.PutFunctionConcurrencyInput{
+		FunctionName:                 aws.String(Resource.ID),
+		ReservedConcurrentExecutions: aws.Int64(0),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return Before, &MutationState{State: map[string]string{"ReservedConcurrency": "0"}}, nil
+}
+
+// S3PublicAccessSettings are the Block Public Access settings, in the order
+// of s3.PublicAccessBlockConfiguration.
+var S3PublicAccessSettings = []string{"BlockPublicAcls", "BlockPublicPolicy", "IgnorePublicAcls", "RestrictPublicBuckets"}
+
+func BlockS3PublicAccess(sess *session.Session, Resource Resource) (*MutationState, *MutationState, error) {
+	svc := s3.New(sess)
+	Before := &MutationState{State: map[string]string{}}
+	result, err := svc.GetPublicAccessBlock(&s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(Resource.ID),
+	})
+	if err != nil {
+		// Buckets without a configuration return NoSuchPublicAccessBlockConfiguration.
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "NoSuchPublicAccessBlockConfiguration" {
+			return nil, nil, err
+		}
+	} else {
+		Config := result.PublicAccessBlockConfiguration
+		for index, Value := range []*bool{Config.BlockPublicAcls, Config.BlockPublicPolicy, Config.IgnorePublicAcls, Config.RestrictPublicBuckets} {
+			Before.State[S3PublicAccessSettings[index]] = strconv.FormatBool(aws.BoolValue(Value))
+		}
+	}
+	_, err = svc.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(Resource.ID),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	After := &MutationState{State: map[string]string{}}
+	for _, Setting := range S3PublicAccessSettings {
+		After.State[Setting] = "true"
+	}
+	return Before, After, nil
+}
+
+// ScheduleVolumeDeletion only deletes unattached volumes. The first run tags
+// the volume with the date it will be deleted after, a run after that date
+// deletes it, so the owner has VolumeDeletionDelay to react. A tag that isn't
+// a date is reset, so the delay restarts.
+func ScheduleVolumeDeletion(sess *session.Session, Resource Resource) (*MutationState, *MutationState, error) {
+	if Resource.Attributes["State"] != ec2.VolumeStateAvailable {
+		return nil, nil, fmt.Errorf("volume is %s, only unattached volumes are deleted", Resource.Attributes["State"])
+	}
+	svc := ec2.New(sess)
+	DeleteAfter, ok := Resource.Tags[VolumeDeletionTag]
+	if _, err := time.Parse(ExemptionDateLayout, DeleteAfter); !ok || err != nil {
+		DeleteAfter = time.Now().Add(VolumeDeletionDelay).Format(ExemptionDateLayout)
+		_, err := svc.CreateTags(&ec2.CreateTagsInput{
+			Resources: aws.StringSlice([]string{Resource.ID}),
+			Tags: []*ec2.Tag{{
+				Key:   aws.String(VolumeDeletionTag),
+				Value: aws.String(DeleteAfter),
+			}},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return &MutationState{Tags: Resource.Tags}, &MutationState{Tags: MergeTags(Resource.Tags, map[string]string{VolumeDeletionTag: DeleteAfter})}, nil
+	}
+	if !DateExpired(DeleteAfter, time.Now()) {
+		return nil, nil, fmt.Errorf("deleted after %s: %w", DeleteAfter, ErrQuarantinePending)
+	}
+	_, err := svc.DeleteVolume(&ec2.DeleteVolumeInput{
+		VolumeId: aws.String(Resource.ID),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &MutationState{State: map[string]string{"State": ec2.VolumeStateAvailable}},
+		&MutationState{State: map[string]string{"State": ec2.VolumeStateDeleted}}, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetQuarantinePlans(t *testing.T) {
+	Now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	PolicyObject := &Policy{Policy: []PolicyRule{
+		{Name: "strict", Mode: ModeEnforce, Quarantine: Quarantine{After: "14d", Actions: map[string]string{"ec2": "stop"}}},
+		{Name: "warn", Mode: ModeWarn, Quarantine: Quarantine{After: "1d", Actions: map[string]string{"ec2": "stop"}}},
+	}}
+	Violation := func(Policy, Mode, ID string) Finding {
+		return Finding{Policy: Policy, Status: StatusViolation, Mode: Mode, Resource: Resource{Type: "ec2", ID: ID, Region: "eu-west-1"}}
+	}
+	Approvals := []Approval{
+		{Resource: "i-approved*", Action: "stop", Approver: "jane"},
+		{Resource: "i-expired", Action: "stop", Approver: "jane", Expires: "2024-01-01"},
+	}
+	Cases := []struct {
+		Name     string
+		Finding  Finding
+		Seen     time.Duration
+		Planned  bool
+		Approved bool
+	}{
+		{"first seen now", Violation("strict", ModeEnforce, "i-new"), -1, false, false},
+		{"not due yet", Violation("strict", ModeEnforce, "i-recent"), 13 * 24 * time.Hour, false, false},
+		{"due, unapproved", Violation("strict", ModeEnforce, "i-old"), 14 * 24 * time.Hour, true, false},
+		{"due, approved", Violation("strict", ModeEnforce, "i-approved-1"), 20 * 24 * time.Hour, true, true},
+		{"due, approval expired", Violation("strict", ModeEnforce, "i-expired"), 20 * 24 * time.Hour, true, false},
+		{"warn mode", Violation("warn", ModeWarn, "i-old"), 20 * 24 * time.Hour, false, false},
+		{"no action for the type", Finding{Policy: "strict", Status: StatusViolation, Mode: ModeEnforce, Resource: Resource{Type: "s3", ID: "b"}}, 20 * 24 * time.Hour, false, false},
+	}
+	for _, Case := range Cases {
+		Key := Case.Finding.Policy + "/" + Case.Finding.Resource.Region + "/" + Case.Finding.Resource.Type + "/" + Case.Finding.Resource.ID
+		State := QuarantineState{}
+		if Case.Seen >= 0 {
+			State[Key] = Now.Add(-Case.Seen)
+		}
+		Plans, NewState := GetQuarantinePlans(PolicyObject, []Finding{Case.Finding}, State, Approvals, Now)
+		if Planned := len(Plans) == 1; Planned != Case.Planned {
+			t.Errorf("%s: planned %v, want %v", Case.Name, Planned, Case.Planned)
+			continue
+		}
+		if Case.Planned && (Plans[0].Approval != nil) != Case.Approved {
+			t.Errorf("%s: approved %v, want %v", Case.Name, Plans[0].Approval != nil, Case.Approved)
+		}
+		Tracked := Case.Finding.Mode == ModeEnforce && Case.Finding.Resource.Type == "ec2"
+		if FirstSeen, ok := NewState[Key]; ok != Tracked {
+			t.Errorf("%s: tracked %v, want %v", Case.Name, ok, Tracked)
+		} else if ok && Case.Seen >= 0 && !FirstSeen.Equal(State[Key]) {
+			t.Errorf("%s: first seen moved from %s to %s", Case.Name, State[Key], FirstSeen)
+		}
+	}
+}
+
+func TestGetQuarantinePlansKeepsUnscanned(t *testing.T) {
+	Now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	PolicyObject := &Policy{Policy: []PolicyRule{
+		{Name: "strict", Mode: ModeEnforce, Quarantine: Quarantine{After: "1d", Actions: map[string]string{"ec2": "stop"}}},
+	}}
+	State := QuarantineState{
+		"strict/eu-west-1/ec2/i-fixed":   Now.Add(-48 * time.Hour),
+		"strict/us-east-1/ec2/i-fixed":   Now.Add(-48 * time.Hour),
+		"strict/eu-west-1/ec2/i-skipped": Now.Add(-48 * time.Hour),
+	}
+	Findings := []Finding{{Policy: "strict", Status: StatusCompliant, Mode: ModeEnforce, Resource: Resource{Type: "ec2", ID: "i-fixed", Region: "eu-west-1"}}}
+	Plans, NewState := GetQuarantinePlans(PolicyObject, Findings, State, nil, Now)
+	Want := QuarantineState{
+		"strict/us-east-1/ec2/i-fixed":   Now.Add(-48 * time.Hour),
+		"strict/eu-west-1/ec2/i-skipped": Now.Add(-48 * time.Hour),
+	}
+	if len(Plans) != 0 || !reflect.DeepEqual(NewState, Want) {
+		t.Errorf("got %v %v, want no plans and %v", Plans, NewState, Want)
+	}
+}
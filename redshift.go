@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/redshift"
+)
+
+func RedshiftFinder(svc *redshift.Redshift, Account *AccountIdentity, ClusterList []*redshift.Cluster, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Cluster := range ClusterList {
+		// DescribeClusters already returns the cluster's tags.
+		Tags := make(map[string]string)
+		for _, Tag := range Cluster.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "redshift-cluster",
+			ID:        *Cluster.ClusterIdentifier,
+			Arn:       Account.Arn("redshift", *svc.Config.Region, "cluster:"+*Cluster.ClusterIdentifier),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Cluster.ClusterCreateTime),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func RedshiftInit(PolicyObject *Policy, sess *session.Session) {
+	svc := redshift.New(sess)
+	// DescribeClusters doesn't return ARNs, they are built from the account.
+	Account, err := GetAccountIdentity(sess)
+	if err != nil {
+		fmt.Printf("Unable to load account identity %v\n", err)
+		return
+	}
+	var Clusters []*redshift.Cluster
+	err = svc.DescribeClustersPages(&redshift.DescribeClustersInput{}, func(page *redshift.DescribeClustersOutput, lastPage bool) bool {
+		Clusters = append(Clusters, page.Clusters...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Redshift Clusters %v\n", err)
+		return
+	}
+	if len(Clusters) == 0 {
+		fmt.Printf("No Redshift Clusters for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := RedshiftFinder(svc, Account, Clusters, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// RemediationPlan is the tags remediation adds to one resource. Skipped lists
+// the missing keys without a default value and Invalid the keys whose value
+// isn't allowed, with that value, which have to be tagged by hand. Lineage
+// lists the tags copied from a parent.
+type RemediationPlan struct {
+	Resource Resource
+	Tags     map[string]string
+	Skipped  []string
+	Invalid  map[string]string
+	Lineage  []InheritedTag
+}
+
+// ResourceTaggers add tags to a resource through its service's tagging API.
+// Resources of other types are tagged by ARN through the Resource Groups
+// Tagging API.
+var ResourceTaggers = map[string]func(*session.Session, Resource, map[string]string) error{
+	"s3":                    TagS3Bucket,
+	"ec2":                   TagEC2Resource,
+	"ec2-eip":               TagEC2Resource,
+	"ec2-image":             TagEC2Resource,
+	"ec2-internetgateway":   TagEC2Resource,
+	"ec2-natgateway":        TagEC2Resource,
+	"ec2-networkacl":        TagEC2Resource,
+	"ec2-reservedinstances": TagEC2Resource,
+	"ec2-routetable":        TagEC2Resource,
+	"ec2-securitygroup":     TagEC2Resource,
+	"ec2-snapshot":          TagEC2Resource,
+	"ec2-launchtemplate":    TagEC2Resource,
+	"ec2-volume":            TagEC2Resource,
+	"ec2-networkinterface":  TagEC2Resource,
+	"elb":                   TagElbLoadBalancer,
+	"elb-targetgroup":       TagElbv2Resource,
+	"elbv2":                 TagElbv2Resource,
+	"elbv2-listener":        TagElbv2Resource,
+	"elbv2-listener-rule":   TagElbv2Resource,
+	"lambda-functions":      TagLambdaFunction,
+}
+
+// ExpandDefault fills in a default value such as "${tags.Team}-${region}"
+// from the resource. It can refer to id, arn, type, region, account and
+// tags.<key> or attributes.<name>. ok is false when a tag or attribute it
+// refers to is missing.
+func ExpandDefault(Default string, Resource Resource) (string, bool) {
+	ok := true
+	Value := os.Expand(Default, func(Name string) string {
+		switch {
+		case Name == "id":
+			return Resource.ID
+		case Name == "arn":
+			return Resource.Arn
+		case Name == "type":
+			return Resource.Type
+		case Name == "region":
+			return Resource.Region
+		case Name == "account":
+			return Resource.Account
+		case strings.HasPrefix(Name, "tags."):
+			Value, found := Resource.Tags[strings.TrimPrefix(Name, "tags.")]
+			ok = ok && found
+			return Value
+		case strings.HasPrefix(Name, "attributes."):
+			Value, found := Resource.Attributes[strings.TrimPrefix(Name, "attributes.")]
+			ok = ok && found
+			return Value
+		}
+		ok = false
+		return ""
+	})
+	return Value, ok && Value != ""
+}
+
+// GetRemediationPlans plans the tags of every violation of policies that
+// aren't in audit mode: the parent's value or else the default of each missing
+// key, and the canonical key of each alias tag with the alias's value. A resource violating several
+// policies gets one plan.
+func GetRemediationPlans(PolicyObject *Policy, Findings []Finding) []RemediationPlan {
+	Rules := make(map[string]PolicyRule)
+	for _, Rule := range PolicyObject.Policy {
+		Rules[Rule.Name] = Rule
+	}
+	var Plans []RemediationPlan
+	PlanIndex := make(map[string]int)
+	for _, Finding := range Findings {
+		if Finding.Mode == ModeAudit || Finding.Status != StatusViolation && len(Finding.Renames) == 0 {
+			continue
+		}
+		ResourceKey := Finding.Resource.Type + "/" + Finding.Resource.ID
+		index, ok := PlanIndex[ResourceKey]
+		if !ok {
+			index = len(Plans)
+			PlanIndex[ResourceKey] = index
+			Plans = append(Plans, RemediationPlan{Resource: Finding.Resource, Tags: make(map[string]string), Invalid: make(map[string]string)})
+		}
+		Plan := &Plans[index]
+		for _, Rename := range Finding.Renames {
+			if _, ok := Plan.Tags[Rename.To]; !ok {
+				Plan.Tags[Rename.To] = Finding.Resource.Tags[Rename.From]
+			}
+		}
+		if Finding.Status != StatusViolation {
+			continue
+		}
+		// Defaults can refer to tags the plan adds, e.g. a renamed alias.
+		Planned := Finding.Resource
+		Planned.Tags = make(map[string]string)
+		for Key, Value := range Finding.Resource.Tags {
+			Planned.Tags[Key] = Value
+		}
+		for Key, Value := range Plan.Tags {
+			Planned.Tags[Key] = Value
+		}
+		for _, Key := range Finding.Missing {
+			if _, ok := Plan.Tags[Key]; ok {
+				continue
+			}
+			if Inherited, found := GetInheritedTag(Finding, Key); found {
+				Plan.Tags[Key] = Inherited.Value
+				Plan.Lineage = append(Plan.Lineage, Inherited)
+				continue
+			}
+			PolicyTag := Rules[Finding.Policy].GetPolicyKey(Key)
+			Value, ok := ExpandDefault(PolicyTag.Default, Planned)
+			if !ok || !PolicyTag.Allows(Value) {
+				if !ContainsString(Plan.Skipped, Key) {
+					Plan.Skipped = append(Plan.Skipped, Key)
+				}
+				continue
+			}
+			Plan.Tags[Key] = Value
+		}
+		for _, Key := range Finding.Invalid {
+			_, Value, _ := Rules[Finding.Policy].FindTag(Finding.Resource, Rules[Finding.Policy].GetPolicyKey(Key))
+			Plan.Invalid[Key] = Value
+		}
+	}
+	// Violations of custom checks alone leave nothing to plan.
+	var Result []RemediationPlan
+	for _, Plan := range Plans {
+		var Skipped []string
+		for _, Key := range Plan.Skipped {
+			if _, ok := Plan.Tags[Key]; !ok {
+				Skipped = append(Skipped, Key)
+			}
+		}
+		Plan.Skipped = Skipped
+		if len(Plan.Tags) > 0 || len(Plan.Skipped) > 0 || len(Plan.Invalid) > 0 {
+			Result = append(Result, Plan)
+		}
+	}
+	return Result
+}
+
+func GetInheritedTag(Finding Finding, Key string) (InheritedTag, bool) {
+	for _, Inherited := range Finding.Inherited {
+		if Inherited.Key == Key {
+			return Inherited, true
+		}
+	}
+	return InheritedTag{}, false
+}
+
+func (Rule PolicyRule) GetPolicyKey(Key string) PolicyKey {
+	for _, PolicyTag := range Rule.Keys {
+		if PolicyTag.Key == Key {
+			return PolicyTag
+		}
+	}
+	return PolicyKey{Key: Key}
+}
+
+func GetSortedKeys(Tags map[string]string) []string {
+	var KeyList []string
+	for Key := range Tags {
+		KeyList = append(KeyList, Key)
+	}
+	sort.Strings(KeyList)
+	return KeyList
+}
+
+func PrintRemediationPlans(Plans []RemediationPlan) {
+	fmt.Println("\n\n\n\nRemediation plan:")
+	for _, Plan := range Plans {
+		fmt.Printf("\n%s %s\n", Plan.Resource.Type, Plan.Resource.ID)
+		for _, Key := range GetSortedKeys(Plan.Tags) {
+			fmt.Printf("  + %s = %s", Key, Plan.Tags[Key])
+			for _, Inherited := range Plan.Lineage {
+				if Inherited.Key == Key {
+					fmt.Printf(" (from %s %s)", Inherited.Parent.Type, Inherited.Parent.ID)
+				}
+			}
+			fmt.Println()
+		}
+		for _, Key := range Plan.Skipped {
+			fmt.Printf("  ! %s has no default value\n", Key)
+		}
+		for _, Key := range GetSortedKeys(Plan.Invalid) {
+			fmt.Printf("  ! %s = %s is not an allowed value, fix by hand\n", Key, Plan.Invalid[Key])
+		}
+	}
+}
+
+// AuditEntry is one line of the journal, written for every write tag-police
+// attempts, tagging, quarantine or rollback. Run is the ID of the command run
+// the write belongs to, Before and After the resource's state around it, so
+// the run can be rolled back.
+type AuditEntry struct {
+	Time     time.Time         `json:"time"`
+	Run      string            `json:"run,omitempty"`
+	Action   string            `json:"action"`
+	Type     string            `json:"type"`
+	ID       string            `json:"id"`
+	Arn      string            `json:"arn,omitempty"`
+	Region   string            `json:"region,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Approver string            `json:"approver,omitempty"`
+	Before   *MutationState    `json:"before,omitempty"`
+	After    *MutationState    `json:"after,omitempty"`
+	Reverts  string            `json:"reverts,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// MutationState is what a write changed on a resource: its tags, and for
+// quarantine actions the settings the action changes, e.g. the instance state.
+type MutationState struct {
+	Tags  map[string]string `json:"tags,omitempty"`
+	State map[string]string `json:"state,omitempty"`
+}
+
+// NewRunID identifies the writes of one command run in the journal.
+func NewRunID() string {
+	return fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102T150405Z"), os.Getpid())
+}
+
+// MergeTags returns the tags with the changes applied, without modifying
+// either.
+func MergeTags(Tags map[string]string, Changes map[string]string) map[string]string {
+	Merged := make(map[string]string)
+	for Key, Value := range Tags {
+		Merged[Key] = Value
+	}
+	for Key, Value := range Changes {
+		Merged[Key] = Value
+	}
+	return Merged
+}
+
+func WriteAuditEntry(AuditLog string, Entry AuditEntry) error {
+	File, err := os.OpenFile(AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer File.Close()
+	Line, err := json.Marshal(Entry)
+	if err != nil {
+		return err
+	}
+	_, err = File.Write(append(Line, '\n'))
+	return err
+}
+
+// ApplyRemediationPlans tags the resources and returns the number of
+// resources that failed.
+func ApplyRemediationPlans(sess *session.Session, Plans []RemediationPlan, Journal string, Run string) int {
+	Failed := 0
+	for _, Plan := range Plans {
+		if len(Plan.Tags) == 0 {
+			continue
+		}
+		Tagger, ok := ResourceTaggers[Plan.Resource.Type]
+		if !ok {
+			Tagger = TagResourceArn
+		}
+		err := Tagger(sess, Plan.Resource, Plan.Tags)
+		Entry := AuditEntry{
+			Time:   time.Now().UTC(),
+			Run:    Run,
+			Action: "tag",
+			Type:   Plan.Resource.Type,
+			ID:     Plan.Resource.ID,
+			Arn:    Plan.Resource.Arn,
+			Region: Plan.Resource.Region,
+			Tags:   Plan.Tags,
+			Before: &MutationState{Tags: Plan.Resource.Tags},
+			After:  &MutationState{Tags: MergeTags(Plan.Resource.Tags, Plan.Tags)},
+		}
+		if err != nil {
+			Failed++
+			Entry.Error = err.Error()
+			fmt.Printf("Unable to tag %s %s %v\n", Plan.Resource.Type, Plan.Resource.ID, err)
+		} else {
+			fmt.Printf("Tagged %s %s\n", Plan.Resource.Type, Plan.Resource.ID)
+		}
+		if err := WriteAuditEntry(Journal, Entry); err != nil {
+			fmt.Printf("Unable to write journal %s %v\n", Journal, err)
+		}
+	}
+	return Failed
+}
+
+// TagS3Bucket merges the tags into the bucket's tags, PutBucketTagging
+// replaces the whole tag set.
+func TagS3Bucket(sess *session.Session, Resource Resource, Tags map[string]string) error {
+	svc := s3.New(sess)
+	Merged, err := GetS3Tags(svc, Resource.ID)
+	if err != nil {
+		return err
+	}
+	for Key, Value := range Tags {
+		Merged[Key] = Value
+	}
+	var TagSet []*s3.Tag
+	for _, Key := range GetSortedKeys(Merged) {
+		TagSet = append(TagSet, &s3.Tag{Key: aws.String(Key), Value: aws.String(Merged[Key])})
+	}
+	_, err = svc.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket:  aws.String(Resource.ID),
+		Tagging: &s3.Tagging{TagSet: TagSet},
+	})
+	return err
+}
+
+func TagEC2Resource(sess *session.Session, Resource Resource, Tags map[string]string) error {
+	var TagList []*ec2.Tag
+	for _, Key := range GetSortedKeys(Tags) {
+		TagList = append(TagList, &ec2.Tag{Key: aws.String(Key), Value: aws.String(Tags[Key])})
+	}
+	_, err := ec2.New(sess).CreateTags(&ec2.CreateTagsInput{
+		Resources: aws.StringSlice([]string{Resource.ID}),
+		Tags:      TagList,
+	})
+	return err
+}
+
+func TagElbLoadBalancer(sess *session.Session, Resource Resource, Tags map[string]string) error {
+	var TagList []*elb.Tag
+	for _, Key := range GetSortedKeys(Tags) {
+		TagList = append(TagList, &elb.Tag{Key: aws.String(Key), Value: aws.String(Tags[Key])})
+	}
+	_, err := elb.New(sess).AddTags(&elb.AddTagsInput{
+		LoadBalancerNames: aws.StringSlice([]string{Resource.ID}),
+		Tags:              TagList,
+	})
+	return err
+}
+
+func TagElbv2Resource(sess *session.Session, Resource Resource, Tags map[string]string) error {
+	var TagList []*elbv2.Tag
+	for _, Key := range GetSortedKeys(Tags) {
+		TagList = append(TagList, &elbv2.Tag{Key: aws.String(Key), Value: aws.String(Tags[Key])})
+	}
+	_, err := elbv2.New(sess).AddTags(&elbv2.AddTagsInput{
+		ResourceArns: aws.StringSlice([]string{Resource.Arn}),
+		Tags:         TagList,
+	})
+	return err
+}
+
+func TagLambdaFunction(sess *session.Session, Resource Resource, Tags map[string]string) error {
+	_, err := lambda.New(sess).TagResource(&lambda.TagResourceInput{
+		Resource: aws.String(Resource.Arn),
+		Tags:     aws.StringMap(Tags),
+	})
+	return err
+}
+
+func TagResourceArn(sess *session.Session, Resource Resource, Tags map[string]string) error {
+	if Resource.Arn == "" {
+		return fmt.Errorf("no tagger for %s and the resource has no ARN", Resource.Type)
+	}
+	result, err := resourcegroupstaggingapi.New(sess).TagResources(&resourcegroupstaggingapi.TagResourcesInput{
+		ResourceARNList: aws.StringSlice([]string{Resource.Arn}),
+		Tags:            aws.StringMap(Tags),
+	})
+	if err != nil {
+		return err
+	}
+	if Failure, ok := result.FailedResourcesMap[Resource.Arn]; ok {
+		return fmt.Errorf("%s: %s", aws.StringValue(Failure.ErrorCode), aws.StringValue(Failure.ErrorMessage))
+	}
+	return nil
+}
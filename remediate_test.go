@@ -0,0 +1,166 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandDefault(t *testing.T) {
+	Resource := Resource{
+		Type:       "ec2",
+		ID:         "i-1",
+		Region:     "eu-west-1",
+		Tags:       map[string]string{"Team": "web"},
+		Attributes: map[string]string{"VpcId": "vpc-1"},
+	}
+	Cases := []struct {
+		Default string
+		Value   string
+		ok      bool
+	}{
+		{"dev", "dev", true},
+		{"${tags.Team}@example.com", "web@example.com", true},
+		{"${type}-${id}-${region}", "ec2-i-1-eu-west-1", true},
+		{"${attributes.VpcId}", "vpc-1", true},
+		{"${tags.Owner}", "", false},
+		{"${unknown}", "", false},
+		{"", "", false},
+	}
+	for _, Case := range Cases {
+		Value, ok := ExpandDefault(Case.Default, Resource)
+		if Value != Case.Value || ok != Case.ok {
+			t.Errorf("%q: got %q %v, want %q %v", Case.Default, Value, ok, Case.Value, Case.ok)
+		}
+	}
+}
+
+func TestGetRemediationPlans(t *testing.T) {
+	PolicyObject := &Policy{Policy: []PolicyRule{
+		{Name: "tags", Keys: []PolicyKey{
+			{Key: "Environment", Default: "dev"},
+			{Key: "Contact", Default: "${tags.Team}@example.com"},
+			{Key: "Team", Values: []string{"web", "data"}},
+			{Key: "Owner"},
+		}},
+		{Name: "cost", Keys: []PolicyKey{{Key: "CostCenter", Default: "100", Values: []string{"1*"}}}},
+	}}
+	Cases := []struct {
+		Name     string
+		Findings []Finding
+		Plans    []RemediationPlan
+	}{
+		{
+			Name: "defaults and skipped keys",
+			Findings: []Finding{{
+				Policy: "tags", Status: StatusViolation, Mode: ModeWarn,
+				Resource: Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"Team": "web"}},
+				Missing:  []string{"Environment", "Contact", "Owner"},
+			}},
+			Plans: []RemediationPlan{{
+				Resource: Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"Team": "web"}},
+				Tags:     map[string]string{"Environment": "dev", "Contact": "web@example.com"},
+				Skipped:  []string{"Owner"},
+				Invalid:  map[string]string{},
+			}},
+		},
+		{
+			Name: "default refers to a missing tag",
+			Findings: []Finding{{
+				Policy: "tags", Status: StatusViolation, Mode: ModeEnforce,
+				Resource: Resource{Type: "ec2", ID: "i-1"},
+				Missing:  []string{"Contact"},
+			}},
+			Plans: []RemediationPlan{{
+				Resource: Resource{Type: "ec2", ID: "i-1"},
+				Tags:     map[string]string{},
+				Skipped:  []string{"Contact"},
+				Invalid:  map[string]string{},
+			}},
+		},
+		{
+			Name: "audit mode is not remediated",
+			Findings: []Finding{{
+				Policy: "tags", Status: StatusViolation, Mode: ModeAudit,
+				Resource: Resource{Type: "ec2", ID: "i-1"},
+				Missing:  []string{"Environment"},
+			}},
+		},
+		{
+			Name: "rename and inherited value",
+			Findings: []Finding{{
+				Policy: "tags", Status: StatusViolation, Mode: ModeWarn,
+				Resource:  Resource{Type: "ec2-volume", ID: "vol-1", Tags: map[string]string{"team": "data"}},
+				Missing:   []string{"Owner"},
+				Renames:   []TagRename{{From: "team", To: "Team"}},
+				Inherited: []InheritedTag{{Key: "Owner", Value: "jane", Parent: ParentResource{Type: "ec2", ID: "i-1"}}},
+			}},
+			Plans: []RemediationPlan{{
+				Resource: Resource{Type: "ec2-volume", ID: "vol-1", Tags: map[string]string{"team": "data"}},
+				Tags:     map[string]string{"Team": "data", "Owner": "jane"},
+				Invalid:  map[string]string{},
+				Lineage:  []InheritedTag{{Key: "Owner", Value: "jane", Parent: ParentResource{Type: "ec2", ID: "i-1"}}},
+			}},
+		},
+		{
+			Name: "invalid values are fixed by hand",
+			Findings: []Finding{{
+				Policy: "tags", Status: StatusViolation, Mode: ModeWarn,
+				Resource: Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"Team": "ops"}},
+				Invalid:  []string{"Team"},
+			}},
+			Plans: []RemediationPlan{{
+				Resource: Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"Team": "ops"}},
+				Tags:     map[string]string{},
+				Invalid:  map[string]string{"Team": "ops"},
+			}},
+		},
+		{
+			Name: "failed checks alone leave nothing to plan",
+			Findings: []Finding{{
+				Policy: "tags", Status: StatusViolation, Mode: ModeWarn,
+				Resource: Resource{Type: "ec2", ID: "i-1"},
+				Failed:   []string{"encrypted: volume is not encrypted"},
+			}},
+		},
+		{
+			Name: "one plan per resource",
+			Findings: []Finding{
+				{Policy: "tags", Status: StatusViolation, Mode: ModeWarn, Resource: Resource{Type: "s3", ID: "b"}, Missing: []string{"Environment"}},
+				{Policy: "cost", Status: StatusViolation, Mode: ModeWarn, Resource: Resource{Type: "s3", ID: "b"}, Missing: []string{"CostCenter"}},
+				{Policy: "cost", Status: StatusCompliant, Mode: ModeWarn, Resource: Resource{Type: "s3", ID: "other"}},
+			},
+			Plans: []RemediationPlan{{
+				Resource: Resource{Type: "s3", ID: "b"},
+				Tags:     map[string]string{"Environment": "dev", "CostCenter": "100"},
+				Invalid:  map[string]string{},
+			}},
+		},
+	}
+	for _, Case := range Cases {
+		Plans := GetRemediationPlans(PolicyObject, Case.Findings)
+		if !reflect.DeepEqual(Plans, Case.Plans) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", Case.Name, Plans, Case.Plans)
+		}
+	}
+}
+
+// Resources tagged by ARN through the Resource Groups Tagging API need their
+// scanner to build the ARN when the service doesn't return it.
+func TestResourceArns(t *testing.T) {
+	Account := &AccountIdentity{Partition: "aws", AccountId: "123456789012"}
+	Cases := []struct {
+		Arn      string
+		Expected string
+	}{
+		{GetQueueArn("eu-west-1", "https://sqs.eu-west-1.amazonaws.com/123456789012/orders"), "arn:aws:sqs:eu-west-1:123456789012:orders"},
+		{GetQueueArn("cn-north-1", "https://sqs.cn-north-1.amazonaws.com.cn/123456789012/orders"), "arn:aws-cn:sqs:cn-north-1:123456789012:orders"},
+		{GetApiGatewayArn("eu-west-1", "/restapis/a1b2c3/stages/prod"), "arn:aws:apigateway:eu-west-1::/restapis/a1b2c3/stages/prod"},
+		{Account.Arn("redshift", "eu-west-1", "cluster:warehouse"), "arn:aws:redshift:eu-west-1:123456789012:cluster:warehouse"},
+		{Account.Arn("workspaces", "eu-west-1", "workspace/ws-1"), "arn:aws:workspaces:eu-west-1:123456789012:workspace/ws-1"},
+	}
+	for _, Case := range Cases {
+		if Case.Arn != Case.Expected {
+			t.Errorf("got %s, want %s", Case.Arn, Case.Expected)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Finding statuses.
+const (
+	StatusCompliant  = "compliant"
+	StatusViolation  = "violation"
+	StatusExempt     = "exempt"
+	StatusSuppressed = "suppressed"
+	StatusPending    = "pending"
+)
+
+// Finding is the outcome of checking one resource against one policy. Note
+// carries extra context such as the exemption a resource is covered by.
+// Invalid lists the keys whose value is not one of the allowed values and
+// Failed the violations of custom checks. Severity is that of the most severe
+// failed key or check and Mode the policy's enforcement mode. Renames are the
+// alias tags the resource should rename to the policy's key, and Inherited
+// the keys found on the resource's parents. Creator is who created the
+// resource, when attribution is enabled and CloudTrail has the event.
+type Finding struct {
+	Policy    string
+	Resource  Resource
+	Status    string
+	Missing   []string
+	Invalid   []string
+	Failed    []string
+	Renames   []TagRename
+	Inherited []InheritedTag
+	Creator   *Creator
+	Severity  string
+	Mode      string
+	Note      string
+}
+
+// AddNote appends to the finding's note, keeping what earlier checks noted,
+// e.g. that the resource's suppression expired.
+func (Finding *Finding) AddNote(Note string) {
+	if Finding.Note != "" {
+		Finding.Note += "; "
+	}
+	Finding.Note += Note
+}
+
+// TagRename suggests renaming a tag key to the canonical policy key, e.g. team
+// to Team.
+type TagRename struct {
+	From string
+	To   string
+}
+
+func (Rename TagRename) String() string {
+	return Rename.From + " -> " + Rename.To
+}
+
+// ReportSections are the statuses listed resource by resource in the report,
+// in order.
+var ReportSections = []struct {
+	Status string
+	Title  string
+}{
+	{StatusViolation, "Violations"},
+	{StatusPending, "Pending"},
+	{StatusExempt, "Exempt"},
+	{StatusSuppressed, "Suppressed"},
+}
+
+// Report collects every finding of the run, in the order they were checked.
+var Report []Finding
+
+func GetFindings(Status string) []Finding {
+	var FindingList []Finding
+	for _, Finding := range Report {
+		if Finding.Status == Status {
+			FindingList = append(FindingList, Finding)
+		}
+	}
+	return FindingList
+}
+
+func PrintReport() {
+	fmt.Println("\n\n\n\nReport:")
+	for _, Section := range ReportSections {
+		FindingList := GetFindings(Section.Status)
+		// Most severe first.
+		sort.SliceStable(FindingList, func(i, j int) bool {
+			return SeverityRank(FindingList[i].Severity) > SeverityRank(FindingList[j].Severity)
+		})
+		fmt.Printf("\n%s (%d):\n", Section.Title, len(FindingList))
+		for _, Finding := range FindingList {
+			fmt.Printf("  - [%s] %s %s", Finding.Policy, Finding.Resource.Type, Finding.Resource.ID)
+			if Finding.Severity != "" {
+				fmt.Printf(" severity: %s mode: %s", Finding.Severity, Finding.Mode)
+			}
+			if len(Finding.Missing) > 0 {
+				fmt.Printf(" missing: %s", strings.Join(Finding.Missing, ", "))
+			}
+			if len(Finding.Invalid) > 0 {
+				fmt.Printf(" invalid: %s", strings.Join(Finding.Invalid, ", "))
+			}
+			if len(Finding.Failed) > 0 {
+				fmt.Printf(" failed: %s", strings.Join(Finding.Failed, "; "))
+			}
+			for _, Inherited := range Finding.Inherited {
+				fmt.Printf(" inherited: %s", Inherited)
+			}
+			if Finding.Creator != nil {
+				fmt.Printf(" created by: %s", Finding.Creator)
+			}
+			if Finding.Note != "" {
+				fmt.Printf(" (%s)", Finding.Note)
+			}
+			fmt.Println()
+		}
+	}
+	var RenameList []Finding
+	for _, Finding := range Report {
+		if len(Finding.Renames) > 0 {
+			RenameList = append(RenameList, Finding)
+		}
+	}
+	fmt.Printf("\nRenames (%d):\n", len(RenameList))
+	for _, Finding := range RenameList {
+		for _, Rename := range Finding.Renames {
+			fmt.Printf("  - [%s] %s %s rename tag %s to %s\n", Finding.Policy, Finding.Resource.Type, Finding.Resource.ID, Rename.From, Rename.To)
+		}
+	}
+	fmt.Printf("\nCompliant: %d\n", len(GetFindings(StatusCompliant)))
+	fmt.Println("Violations by severity:")
+	for Rank := len(Severities) - 1; Rank >= 0; Rank-- {
+		Counts := make(map[string]int)
+		for _, Finding := range GetFindings(StatusViolation) {
+			if Finding.Severity == Severities[Rank] {
+				Counts[Finding.Mode]++
+			}
+		}
+		fmt.Printf("  %s: %d enforce, %d warn, %d audit\n", Severities[Rank], Counts[ModeEnforce], Counts[ModeWarn], Counts[ModeAudit])
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Resource is a single taggable cloud resource as collected by a scanner.
+// Type is the policy resource identifier (e.g. "elbv2") and Tags holds the
+// resource's tags keyed by tag key. Attributes carries extra details reported
+// with the finding, such as a log group's retention. CreatedAt is zero when the
+// service does not report a creation time. Region and Account are where the
+// resource was scanned. Parents are the resources it belongs to, whose tags it
+// can inherit.
+type Resource struct {
+	Type       string
+	ID         string
+	Arn        string
+	Region     string
+	Account    string
+	Tags       map[string]string
+	Attributes map[string]string
+	CreatedAt  time.Time
+	Parents    []ParentResource
+}
+
+// LambdaTimeLayout is the layout of a Lambda function's LastModified.
+const LambdaTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+func ParseLambdaTime(Value string) time.Time {
+	Time, _ := time.Parse(LambdaTimeLayout, Value)
+	return Time
+}
+
+func ParseTime(Value string) time.Time {
+	// RFC 3339 timestamps returned as strings, e.g. an AMI's CreationDate.
+	Time, _ := time.Parse(time.RFC3339, Value)
+	return Time
+}
+
+func GetResourceTagKeys(Resource Resource) []string {
+	var KeyList []string
+	for Key := range Resource.Tags {
+		KeyList = append(KeyList, Key)
+	}
+	return KeyList
+}
+
+func GetResourceAttributeNames(Resource Resource) []string {
+	var NameList []string
+	for Name := range Resource.Attributes {
+		NameList = append(NameList, Name)
+	}
+	sort.Strings(NameList)
+	return NameList
+}
+
+// IsCaseInsensitive reports whether keys match regardless of case, false
+// unless the policy or the policy it extends sets caseinsenstive.
+func (Rule PolicyRule) IsCaseInsensitive() bool {
+	return Rule.Caseinsenstive != nil && *Rule.Caseinsenstive
+}
+
+// FindTag looks up the tag of the resource that satisfies a policy key: the
+// key itself, or one of its aliases. Keys are compared ignoring case when the
+// policy is case insensitive. It returns the tag key found, which differs
+// from the policy key when the tag should be renamed.
+func (Rule PolicyRule) FindTag(Resource Resource, PolicyTag PolicyKey) (string, string, bool) {
+	if Value, ok := Resource.Tags[PolicyTag.Key]; ok {
+		return PolicyTag.Key, Value, true
+	}
+	TagKeys := GetResourceTagKeys(Resource)
+	sort.Strings(TagKeys)
+	for _, Name := range append([]string{PolicyTag.Key}, PolicyTag.Aliases...) {
+		for _, TagKey := range TagKeys {
+			if TagKey == Name || Rule.IsCaseInsensitive() && strings.EqualFold(TagKey, Name) {
+				return TagKey, Resource.Tags[TagKey], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// EvaluateResource checks one resource against a policy rule. It makes no AWS
+// calls, so every Finder and offline check share it. The second result is
+// false when the rule's selector does not select the resource.
+func EvaluateResource(Rule PolicyRule, Resource Resource, Now time.Time) (Finding, bool) {
+	Finding := Finding{
+		Policy:   Rule.Name,
+		Resource: Resource,
+		Status:   StatusCompliant,
+		Mode:     Rule.GetMode(),
+	}
+	if !Rule.Selector.Selects(Resource) {
+		return Finding, false
+	}
+	if Reason, Until, ok := Rule.Suppression.GetSuppression(Resource); ok {
+		if !DateExpired(Until, Now) {
+			Finding.Status = StatusSuppressed
+			Finding.Note = Reason
+			if Until != "" {
+				Finding.Note += ", until " + Until
+			}
+			return Finding, true
+		}
+		Finding.Note = "suppression expired " + Until
+	}
+	for _, PolicyTag := range Rule.Keys {
+		if !PolicyTag.Required(Resource) {
+			continue
+		}
+		TagKey, Value, ok := Rule.FindTag(Resource, PolicyTag)
+		if ok && TagKey != PolicyTag.Key {
+			Finding.Renames = append(Finding.Renames, TagRename{From: TagKey, To: PolicyTag.Key})
+		}
+		if !ok && Rule.Inherit != "" {
+			if Inherited, found := Rule.FindInheritedTag(Resource, PolicyTag); found {
+				Finding.Inherited = append(Finding.Inherited, Inherited)
+				if Rule.Inherit == InheritSatisfy {
+					Value, ok = Inherited.Value, true
+				}
+			}
+		}
+		if !ok {
+			Finding.Missing = append(Finding.Missing, PolicyTag.Key)
+		} else if !PolicyTag.Allows(Value) {
+			Finding.Invalid = append(Finding.Invalid, PolicyTag.Key)
+		} else {
+			continue
+		}
+		// The finding is as severe as its most severe failed key.
+		if Severity := Rule.GetKeySeverity(PolicyTag); SeverityRank(Severity) > SeverityRank(Finding.Severity) {
+			Finding.Severity = Severity
+		}
+	}
+	for _, Check := range Rule.Checks {
+		Violations := Check.Evaluate(Resource)
+		if len(Violations) == 0 {
+			continue
+		}
+		Finding.Failed = append(Finding.Failed, Violations...)
+		if Severity := Rule.GetCheckSeverity(Check); SeverityRank(Severity) > SeverityRank(Finding.Severity) {
+			Finding.Severity = Severity
+		}
+	}
+	if len(Finding.Missing) == 0 && len(Finding.Invalid) == 0 && len(Finding.Failed) == 0 {
+		return Finding, true
+	}
+	Finding.Status = StatusViolation
+	if GracePeriod := Rule.GetGracePeriod(); !Resource.CreatedAt.IsZero() && Now.Sub(Resource.CreatedAt) < GracePeriod {
+		Finding.Status = StatusPending
+		Finding.AddNote("created " + Resource.CreatedAt.Format(time.RFC3339) + ", grace period " + Rule.GracePeriod)
+		return Finding, true
+	}
+	if Exemption, ok := GetExemption(Rule.Name, Resource, Now); ok {
+		if Exemption.Expired(Now) {
+			Finding.AddNote("exemption expired " + Exemption.Expires)
+		} else {
+			Finding.Status = StatusExempt
+			Finding.AddNote(Exemption.Reason + ", owner " + Exemption.Owner + ", expires " + Exemption.Expires)
+		}
+	}
+	return Finding, true
+}
+
+func ResourceFinder(Resources []Resource, Rule PolicyRule) ([]string, []string) {
+	var TaggedResources, UnTaggedResources []string
+	for _, Resource := range Resources {
+		if Resource.Region == "" {
+			Resource.Region = CurrentRegion
+		}
+		if Resource.Account == "" && CurrentAccount != nil {
+			Resource.Account = CurrentAccount.AccountId
+		}
+		fmt.Print("\n\n\n")
+		fmt.Println("Name: ", Resource.ID)
+		if Resource.Arn != "" {
+			fmt.Println("Arn: ", Resource.Arn)
+		}
+		for _, Name := range GetResourceAttributeNames(Resource) {
+			fmt.Println(Name+": ", Resource.Attributes[Name])
+		}
+		if !Resource.CreatedAt.IsZero() {
+			fmt.Println("Created: ", Resource.CreatedAt.Format(time.RFC3339))
+		}
+		fmt.Println("-->", GetResourceTagKeys(Resource))
+		Finding, Selected := EvaluateResource(Rule, Resource, time.Now())
+		if !Selected {
+			fmt.Println("Skipped by selector.")
+			continue
+		}
+		Report = append(Report, Finding)
+		switch Finding.Status {
+		case StatusCompliant:
+			if len(Finding.Renames) > 0 {
+				fmt.Println("TagCheck Success, with aliases.")
+			} else {
+				fmt.Println("TagCheck Success.")
+			}
+			TaggedResources = append(TaggedResources, Resource.ID)
+		case StatusViolation:
+			if Finding.Mode == ModeAudit {
+				fmt.Println("TagCheck Audit. Missing:", Finding.Missing, "Invalid:", Finding.Invalid, "Severity:", Finding.Severity)
+			} else {
+				fmt.Println("TagCheck Failed. Missing:", Finding.Missing, "Invalid:", Finding.Invalid, "Severity:", Finding.Severity)
+			}
+			for _, Failed := range Finding.Failed {
+				fmt.Println("Check Failed:", Failed)
+			}
+			UnTaggedResources = append(UnTaggedResources, Resource.ID)
+		case StatusExempt:
+			fmt.Println("TagCheck Exempt.", Finding.Note)
+		case StatusSuppressed:
+			fmt.Println("TagCheck Suppressed.")
+		case StatusPending:
+			fmt.Println("TagCheck Pending. Missing:", Finding.Missing)
+		}
+		for _, Rename := range Finding.Renames {
+			fmt.Println("Rename:", Rename)
+		}
+		for _, Inherited := range Finding.Inherited {
+			fmt.Println("Inherited:", Inherited)
+		}
+		if Finding.Note != "" && Finding.Status != StatusExempt {
+			fmt.Println(Finding.Status+":", Finding.Note)
+		}
+	}
+	return TaggedResources, UnTaggedResources
+}
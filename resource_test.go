@@ -0,0 +1,317 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestNow is the time resources are evaluated at in the tests.
+var TestNow = time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+func TestResourceMatch(t *testing.T) {
+	Resource := Resource{
+		Type:       "ec2",
+		ID:         "i-1",
+		Arn:        "arn:aws:ec2:eu-west-1:123456789012:instance/i-1",
+		Region:     "eu-west-1",
+		Account:    "123456789012",
+		Tags:       map[string]string{"Team": "web"},
+		Attributes: map[string]string{"VpcId": "vpc-1"},
+	}
+	Cases := []struct {
+		Name    string
+		Match   ResourceMatch
+		Matches bool
+	}{
+		{"empty", ResourceMatch{}, true},
+		{"name", ResourceMatch{Name: "i-*"}, true},
+		{"arn", ResourceMatch{Arn: "arn:aws:ec2:*:instance/*"}, true},
+		{"types", ResourceMatch{Types: []string{"s3", "ec2"}}, true},
+		{"other type", ResourceMatch{Types: []string{"s3"}}, false},
+		{"region", ResourceMatch{Regions: []string{"eu-*"}}, true},
+		{"other region", ResourceMatch{Regions: []string{"us-*"}}, false},
+		{"account", ResourceMatch{Accounts: []string{"123456789012"}}, true},
+		{"tag", ResourceMatch{Tags: map[string]string{"Team": "w*"}}, true},
+		{"other tag value", ResourceMatch{Tags: map[string]string{"Team": "data"}}, false},
+		{"attribute", ResourceMatch{Attributes: map[string]string{"VpcId": "vpc-1"}}, true},
+		{"missing attribute", ResourceMatch{Attributes: map[string]string{"SubnetId": "*"}}, false},
+		{"every field must match", ResourceMatch{Types: []string{"ec2"}, Regions: []string{"us-*"}}, false},
+	}
+	for _, Case := range Cases {
+		if Matches := Case.Match.Matches(Resource); Matches != Case.Matches {
+			t.Errorf("%s: got %v, want %v", Case.Name, Matches, Case.Matches)
+		}
+	}
+}
+
+func TestEvaluateResourceSelector(t *testing.T) {
+	Rule := PolicyRule{
+		Name: "production",
+		Keys: []PolicyKey{{Key: "Team"}},
+		Selector: Selector{
+			Include: []ResourceMatch{{Name: "prod-*"}, {Tags: map[string]string{"Environment": "prod"}}},
+			Exclude: []ResourceMatch{{Tags: map[string]string{"aws:cloudformation:*": "*"}}},
+		},
+	}
+	Cases := []struct {
+		Name     string
+		Resource Resource
+		Selected bool
+		Status   string
+	}{
+		{"included by name", Resource{Type: "s3", ID: "prod-logs"}, true, StatusViolation},
+		{"included by tag", Resource{Type: "s3", ID: "logs", Tags: map[string]string{"Environment": "prod", "Team": "web"}}, true, StatusCompliant},
+		{"not included", Resource{Type: "s3", ID: "dev-logs", Tags: map[string]string{"Environment": "dev"}}, false, ""},
+		{"excluded", Resource{Type: "s3", ID: "prod-stack", Tags: map[string]string{"aws:cloudformation:stack-name": "web"}}, false, ""},
+	}
+	for _, Case := range Cases {
+		Finding, Selected := EvaluateResource(Rule, Case.Resource, TestNow)
+		if Selected != Case.Selected {
+			t.Errorf("%s: got selected %v, want %v", Case.Name, Selected, Case.Selected)
+			continue
+		}
+		if Selected && Finding.Status != Case.Status {
+			t.Errorf("%s: got %s, want %s", Case.Name, Finding.Status, Case.Status)
+		}
+	}
+}
+
+func TestEvaluateResourceExemptions(t *testing.T) {
+	defer func(Saved []Exemption) { ExemptionList = Saved }(ExemptionList)
+	ExemptionList = []Exemption{
+		{Resource: "vendor-*", Reason: "managed by the vendor", Owner: "platform", Expires: "2026-12-31"},
+		{Resource: "arn:aws:s3:::old-*", Reason: "migration", Owner: "data", Expires: "2026-01-30"},
+		{Policy: "other", Resource: "legacy", Reason: "other policy only", Owner: "ops", Expires: "2026-12-31"},
+		{Resource: "today", Reason: "last day", Owner: "ops", Expires: "2026-01-31"},
+	}
+	Rule := PolicyRule{Name: "global", Keys: []PolicyKey{{Key: "Team"}}}
+	Cases := []struct {
+		Name     string
+		Resource Resource
+		Status   string
+		Note     string
+	}{
+		{"exempt", Resource{Type: "s3", ID: "vendor-logs"}, StatusExempt, "managed by the vendor, owner platform, expires 2026-12-31"},
+		{"expired, matched by arn", Resource{Type: "s3", ID: "bucket", Arn: "arn:aws:s3:::old-logs"}, StatusViolation, "exemption expired 2026-01-30"},
+		{"exemption of another policy", Resource{Type: "s3", ID: "legacy"}, StatusViolation, ""},
+		{"valid through its expiry day", Resource{Type: "s3", ID: "today"}, StatusExempt, "last day, owner ops, expires 2026-01-31"},
+		{"compliant", Resource{Type: "s3", ID: "vendor-tagged", Tags: map[string]string{"Team": "web"}}, StatusCompliant, ""},
+	}
+	for _, Case := range Cases {
+		Finding, _ := EvaluateResource(Rule, Case.Resource, TestNow)
+		if Finding.Status != Case.Status || Finding.Note != Case.Note {
+			t.Errorf("%s: got %s %q, want %s %q", Case.Name, Finding.Status, Finding.Note, Case.Status, Case.Note)
+		}
+	}
+}
+
+func TestEvaluateResourceSuppression(t *testing.T) {
+	defer func(Saved []Exemption) { ExemptionList = Saved }(ExemptionList)
+	ExemptionList = []Exemption{{Resource: "exempt", Reason: "vendor", Owner: "ops", Expires: "2026-12-31"}}
+	Rule := PolicyRule{
+		Name:        "global",
+		Keys:        []PolicyKey{{Key: "Team"}},
+		Suppression: Suppression{Disabled: []string{"iam-role"}},
+	}
+	Cases := []struct {
+		Name     string
+		Resource Resource
+		Status   string
+		Note     string
+	}{
+		{"suppressed", Resource{Type: "s3", ID: "a", Tags: map[string]string{"tag-police:ignore": "legacy"}}, StatusSuppressed, "legacy"},
+		{"suppressed until", Resource{Type: "s3", ID: "a", Tags: map[string]string{"tag-police:ignore": "legacy until 2026-02-01"}}, StatusSuppressed, "legacy, until 2026-02-01"},
+		{"suppression expired", Resource{Type: "s3", ID: "a", Tags: map[string]string{"tag-police:ignore": "legacy until 2026-01-01"}}, StatusViolation, "suppression expired 2026-01-01"},
+		{"suppression expired, exempt", Resource{Type: "s3", ID: "exempt", Tags: map[string]string{"tag-police:ignore": "legacy until 2026-01-01"}}, StatusExempt, "suppression expired 2026-01-01; vendor, owner ops, expires 2026-12-31"},
+		{"type can't suppress itself", Resource{Type: "iam-role", ID: "a", Tags: map[string]string{"tag-police:ignore": "legacy"}}, StatusViolation, ""},
+	}
+	for _, Case := range Cases {
+		Finding, _ := EvaluateResource(Rule, Case.Resource, TestNow)
+		if Finding.Status != Case.Status || Finding.Note != Case.Note {
+			t.Errorf("%s: got %s %q, want %s %q", Case.Name, Finding.Status, Finding.Note, Case.Status, Case.Note)
+		}
+	}
+}
+
+func TestParseGracePeriod(t *testing.T) {
+	Cases := []struct {
+		Value       string
+		GracePeriod time.Duration
+		Valid       bool
+	}{
+		{"", 0, true},
+		{"30m", 30 * time.Minute, true},
+		{"6h", 6 * time.Hour, true},
+		{"2d", 48 * time.Hour, true},
+		{"xd", 0, false},
+		{"soon", 0, false},
+	}
+	for _, Case := range Cases {
+		GracePeriod, err := ParseGracePeriod(Case.Value)
+		if GracePeriod != Case.GracePeriod || (err == nil) != Case.Valid {
+			t.Errorf("%q: got %v %v, want %v valid %v", Case.Value, GracePeriod, err, Case.GracePeriod, Case.Valid)
+		}
+	}
+}
+
+func TestEvaluateResourceGracePeriod(t *testing.T) {
+	Rule := PolicyRule{Name: "global", Keys: []PolicyKey{{Key: "Team"}}, GracePeriod: "2d"}
+	Cases := []struct {
+		Name     string
+		Resource Resource
+		Status   string
+		Note     string
+	}{
+		{"new", Resource{Type: "ec2", ID: "i-1", CreatedAt: TestNow.Add(-24 * time.Hour)}, StatusPending, "created 2026-01-30T12:00:00Z, grace period 2d"},
+		{"past the grace period", Resource{Type: "ec2", ID: "i-2", CreatedAt: TestNow.Add(-72 * time.Hour)}, StatusViolation, ""},
+		{"unknown creation time", Resource{Type: "ec2", ID: "i-3"}, StatusViolation, ""},
+		{"new and tagged", Resource{Type: "ec2", ID: "i-4", CreatedAt: TestNow, Tags: map[string]string{"Team": "web"}}, StatusCompliant, ""},
+	}
+	for _, Case := range Cases {
+		Finding, _ := EvaluateResource(Rule, Case.Resource, TestNow)
+		if Finding.Status != Case.Status || Finding.Note != Case.Note {
+			t.Errorf("%s: got %s %q, want %s %q", Case.Name, Finding.Status, Finding.Note, Case.Status, Case.Note)
+		}
+	}
+}
+
+func TestEvaluateResourceWhen(t *testing.T) {
+	Rule := PolicyRule{
+		Name: "global",
+		Keys: []PolicyKey{
+			{Key: "Team"},
+			{Key: "DataClassification", When: ResourceMatch{Tags: map[string]string{"Environment": "prod"}}},
+			{Key: "BackupPolicy", When: ResourceMatch{Types: []string{"ec2-volume", "rds"}}},
+		},
+	}
+	Cases := []struct {
+		Name     string
+		Resource Resource
+		Missing  []string
+	}{
+		{"no condition matches", Resource{Type: "s3", ID: "a", Tags: map[string]string{"Environment": "dev"}}, []string{"Team"}},
+		{"production", Resource{Type: "s3", ID: "a", Tags: map[string]string{"Environment": "prod"}}, []string{"Team", "DataClassification"}},
+		{"volume", Resource{Type: "ec2-volume", ID: "vol-1", Tags: map[string]string{"Team": "web"}}, []string{"BackupPolicy"}},
+		{"production volume", Resource{Type: "ec2-volume", ID: "vol-2", Tags: map[string]string{"Environment": "prod"}}, []string{"Team", "DataClassification", "BackupPolicy"}},
+	}
+	for _, Case := range Cases {
+		Finding, _ := EvaluateResource(Rule, Case.Resource, TestNow)
+		if !reflect.DeepEqual(Finding.Missing, Case.Missing) {
+			t.Errorf("%s: got missing %v, want %v", Case.Name, Finding.Missing, Case.Missing)
+		}
+	}
+}
+
+func TestEvaluateResourceSeverity(t *testing.T) {
+	Rule := PolicyRule{
+		Name:     "global",
+		Severity: SeverityLow,
+		Mode:     ModeEnforce,
+		Keys:     []PolicyKey{{Key: "Name"}, {Key: "Team", Severity: SeverityHigh}, {Key: "Environment", Values: []string{"prod", "dev"}, Severity: SeverityCritical}},
+	}
+	Cases := []struct {
+		Name     string
+		Tags     map[string]string
+		Severity string
+	}{
+		{"policy severity", map[string]string{"Team": "web", "Environment": "dev"}, SeverityLow},
+		{"most severe missing key", map[string]string{"Environment": "dev"}, SeverityHigh},
+		{"invalid value", map[string]string{"Name": "a", "Team": "web", "Environment": "test"}, SeverityCritical},
+		{"compliant", map[string]string{"Name": "a", "Team": "web", "Environment": "prod"}, ""},
+	}
+	for _, Case := range Cases {
+		Finding, _ := EvaluateResource(Rule, Resource{Type: "ec2", ID: "i-1", Tags: Case.Tags}, TestNow)
+		if Finding.Severity != Case.Severity || Finding.Mode != ModeEnforce {
+			t.Errorf("%s: got %s %s, want %s %s", Case.Name, Finding.Severity, Finding.Mode, Case.Severity, ModeEnforce)
+		}
+	}
+	if Finding, _ := EvaluateResource(PolicyRule{Name: "default", Keys: []PolicyKey{{Key: "Team"}}}, Resource{Type: "ec2", ID: "i-1"}, TestNow); Finding.Severity != DefaultSeverity || Finding.Mode != DefaultMode {
+		t.Errorf("defaults: got %s %s, want %s %s", Finding.Severity, Finding.Mode, DefaultSeverity, DefaultMode)
+	}
+}
+
+func TestEvaluateResourceChecks(t *testing.T) {
+	Rule := PolicyRule{
+		Name:     "global",
+		Severity: SeverityLow,
+		Checks: []CustomCheck{
+			{Name: "owner-email", Expression: `!has(tags.Owner) || tags.Owner.endsWith("@example.com")`, Message: "Owner must be an example.com email address", Severity: SeverityHigh},
+			{Name: "name-prefix", Expression: `has(tags.Environment) && !tags.Name.startsWith(tags.Environment + "-") ? ["Name must start with " + tags.Environment + "-"] : []`},
+			{Name: "region", Expression: `region == "eu-west-1"`},
+		},
+	}
+	Cases := []struct {
+		Name     string
+		Resource Resource
+		Failed   []string
+		Severity string
+	}{
+		{"passes", Resource{Type: "ec2", ID: "i-1", Region: "eu-west-1", Tags: map[string]string{"Owner": "a@example.com", "Environment": "prod", "Name": "prod-web"}}, nil, ""},
+		{"message", Resource{Type: "ec2", ID: "i-1", Region: "eu-west-1", Tags: map[string]string{"Owner": "a@example.org"}}, []string{"owner-email: Owner must be an example.com email address"}, SeverityHigh},
+		{"list of messages", Resource{Type: "ec2", ID: "i-1", Region: "eu-west-1", Tags: map[string]string{"Environment": "prod", "Name": "web"}}, []string{"name-prefix: Name must start with prod-"}, SeverityLow},
+		{"no message", Resource{Type: "ec2", ID: "i-1", Region: "us-east-1"}, []string{"region: failed"}, SeverityLow},
+	}
+	for _, Case := range Cases {
+		Finding, _ := EvaluateResource(Rule, Case.Resource, TestNow)
+		if !reflect.DeepEqual(Finding.Failed, Case.Failed) || Finding.Severity != Case.Severity {
+			t.Errorf("%s: got %q %s, want %q %s", Case.Name, Finding.Failed, Finding.Severity, Case.Failed, Case.Severity)
+		}
+		Status := StatusViolation
+		if Case.Failed == nil {
+			Status = StatusCompliant
+		}
+		if Finding.Status != Status {
+			t.Errorf("%s: got %s, want %s", Case.Name, Finding.Status, Status)
+		}
+	}
+
+	// An expression that errors, here on a missing tag, is a violation.
+	Failing := CustomCheck{Name: "name", Expression: `tags.Name != ""`}
+	if Violations := Failing.Evaluate(Resource{Type: "ec2", ID: "i-1"}); len(Violations) != 1 {
+		t.Errorf("missing tag: got %q, want one violation", Violations)
+	}
+}
+
+func TestFindTag(t *testing.T) {
+	True := true
+	PolicyTag := PolicyKey{Key: "CostCenter", Aliases: []string{"cost-center", "costcentre"}}
+	Cases := []struct {
+		Name            string
+		CaseInsensitive *bool
+		Tags            map[string]string
+		TagKey          string
+		Found           bool
+	}{
+		{"key", nil, map[string]string{"CostCenter": "1", "cost-center": "2"}, "CostCenter", true},
+		{"alias", nil, map[string]string{"costcentre": "1"}, "costcentre", true},
+		{"first alias wins", nil, map[string]string{"costcentre": "1", "cost-center": "2"}, "cost-center", true},
+		{"other case", nil, map[string]string{"costcenter": "1"}, "", false},
+		{"other case, case insensitive", &True, map[string]string{"costcenter": "1"}, "costcenter", true},
+		{"alias in other case, case insensitive", &True, map[string]string{"Cost-Center": "1"}, "Cost-Center", true},
+		{"missing", &True, map[string]string{"Team": "web"}, "", false},
+	}
+	for _, Case := range Cases {
+		Rule := PolicyRule{Name: "global", Caseinsenstive: Case.CaseInsensitive}
+		TagKey, Value, Found := Rule.FindTag(Resource{Type: "ec2", ID: "i-1", Tags: Case.Tags}, PolicyTag)
+		if TagKey != Case.TagKey || Found != Case.Found || Value != Case.Tags[Case.TagKey] {
+			t.Errorf("%s: got %q %q %v, want %q %v", Case.Name, TagKey, Value, Found, Case.TagKey, Case.Found)
+		}
+	}
+}
+
+func TestEvaluateResourceAliases(t *testing.T) {
+	Rule := PolicyRule{
+		Name: "global",
+		Keys: []PolicyKey{{Key: "Team", Aliases: []string{"team"}}, {Key: "Environment", Aliases: []string{"env"}, Values: []string{"prod", "dev"}}},
+	}
+	Finding, _ := EvaluateResource(Rule, Resource{Type: "ec2", ID: "i-1", Tags: map[string]string{"team": "web", "env": "test"}}, TestNow)
+	Renames := []TagRename{{From: "team", To: "Team"}, {From: "env", To: "Environment"}}
+	if !reflect.DeepEqual(Finding.Renames, Renames) {
+		t.Errorf("got renames %v, want %v", Finding.Renames, Renames)
+	}
+	// An alias satisfies the key, but its value must still be allowed.
+	if Finding.Status != StatusViolation || Finding.Missing != nil || !reflect.DeepEqual(Finding.Invalid, []string{"Environment"}) {
+		t.Errorf("got %s missing %v invalid %v, want %s invalid [Environment]", Finding.Status, Finding.Missing, Finding.Invalid, StatusViolation)
+	}
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ResourceUntaggers remove tags, the counterpart of ResourceTaggers.
+// TagResourceArn's counterpart UntagResourceArn is the fallback.
+var ResourceUntaggers = map[string]func(*session.Session, Resource, []string) error{
+	"s3":                    UntagS3Bucket,
+	"ec2":                   UntagEC2Resource,
+	"ec2-eip":               UntagEC2Resource,
+	"ec2-image":             UntagEC2Resource,
+	"ec2-internetgateway":   UntagEC2Resource,
+	"ec2-natgateway":        UntagEC2Resource,
+	"ec2-networkacl":        UntagEC2Resource,
+	"ec2-reservedinstances": UntagEC2Resource,
+	"ec2-routetable":        UntagEC2Resource,
+	"ec2-securitygroup":     UntagEC2Resource,
+	"ec2-snapshot":          UntagEC2Resource,
+	"ec2-launchtemplate":    UntagEC2Resource,
+	"ec2-volume":            UntagEC2Resource,
+	"ec2-networkinterface":  UntagEC2Resource,
+	"elb":                   UntagElbLoadBalancer,
+	"elb-targetgroup":       UntagElbv2Resource,
+	"elbv2":                 UntagElbv2Resource,
+	"elbv2-listener":        UntagElbv2Resource,
+	"elbv2-listener-rule":   UntagElbv2Resource,
+	"lambda-functions":      UntagLambdaFunction,
+}
+
+// StateRestorers put back the state a quarantine action changed, keyed by
+// action.
+var StateRestorers = map[string]func(*session.Session, Resource, map[string]string) error{
+	"stop":                RestoreEC2InstanceState,
+	"disable":             RestoreLambdaConcurrency,
+	"block-public-access": RestoreS3PublicAccess,
+	"delete":              RestoreVolume,
+}
+
+func GetJournal(filePath string) ([]AuditEntry, error) {
+	File, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer File.Close()
+	var Entries []AuditEntry
+	Scanner := bufio.NewScanner(File)
+	Scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for Line := 1; Scanner.Scan(); Line++ {
+		if len(strings.TrimSpace(Scanner.Text())) == 0 {
+			continue
+		}
+		var Entry AuditEntry
+		if err := json.Unmarshal(Scanner.Bytes(), &Entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filePath, Line, err)
+		}
+		Entries = append(Entries, Entry)
+	}
+	return Entries, Scanner.Err()
+}
+
+// GetRunEntries returns the successful writes of a run that can be rolled
+// back, latest first, the order they are undone in. Writes an earlier
+// rollback already undid are left out, so a failed rollback can be rerun.
+func GetRunEntries(Entries []AuditEntry, Run string) []AuditEntry {
+	Reverted := make(map[string]bool)
+	for _, Entry := range Entries {
+		if Entry.Reverts == Run && Entry.Error == "" {
+			Reverted[Entry.Action+"/"+Entry.Type+"/"+Entry.ID] = true
+		}
+	}
+	var RunEntries []AuditEntry
+	for index := len(Entries) - 1; index >= 0; index-- {
+		Entry := Entries[index]
+		if Entry.Run != Run || Entry.Error != "" || Entry.Before == nil || Entry.After == nil {
+			continue
+		}
+		if !Reverted["rollback:"+Entry.Action+"/"+Entry.Type+"/"+Entry.ID] {
+			RunEntries = append(RunEntries, Entry)
+		}
+	}
+	return RunEntries
+}
+
+func (Entry AuditEntry) Resource() Resource {
+	return Resource{Type: Entry.Type, ID: Entry.ID, Arn: Entry.Arn, Region: Entry.Region}
+}
+
+// QuarantineAction is the quarantine action of the entry, also for the
+// rollback of a quarantine, "" for tagging.
+func (Entry AuditEntry) QuarantineAction() string {
+	Action := Entry.Action
+	for strings.HasPrefix(Action, "rollback:") {
+		Action = strings.TrimPrefix(Action, "rollback:")
+	}
+	if !strings.HasPrefix(Action, "quarantine:") {
+		return ""
+	}
+	return strings.TrimPrefix(Action, "quarantine:")
+}
+
+// GetTagChanges returns the tags to remove and the tags to set to go from
+// After back to Before.
+func GetTagChanges(Before map[string]string, After map[string]string) ([]string, map[string]string) {
+	var Removed []string
+	Restored := make(map[string]string)
+	for _, Key := range GetSortedKeys(After) {
+		if _, ok := Before[Key]; !ok {
+			Removed = append(Removed, Key)
+		}
+	}
+	for Key, Value := range Before {
+		if Current, ok := After[Key]; !ok || Current != Value {
+			Restored[Key] = Value
+		}
+	}
+	return Removed, Restored
+}
+
+func PrintRollbackPlan(Entries []AuditEntry) {
+	for _, Entry := range Entries {
+		fmt.Printf("%s %s (%s):\n", Entry.Type, Entry.ID, Entry.Action)
+		if Entry.QuarantineAction() != "" {
+			for _, Key := range GetSortedKeys(MergeTags(Entry.Before.State, Entry.After.State)) {
+				Before, ok := Entry.Before.State[Key]
+				if !ok {
+					fmt.Printf("  ~ reset %s (now %s)\n", Key, Entry.After.State[Key])
+				} else if Before != Entry.After.State[Key] {
+					fmt.Printf("  ~ %s: %s -> %s\n", Key, Entry.After.State[Key], Before)
+				}
+			}
+		}
+		Removed, Restored := GetTagChanges(Entry.Before.Tags, Entry.After.Tags)
+		for _, Key := range Removed {
+			fmt.Printf("  - %s\n", Key)
+		}
+		for _, Key := range GetSortedKeys(Restored) {
+			fmt.Printf("  + %s = %s\n", Key, Restored[Key])
+		}
+	}
+}
+
+// RollbackEntry puts the resource back in the entry's Before state.
+func RollbackEntry(sess *session.Session, Entry AuditEntry) error {
+	Resource := Entry.Resource()
+	if Action := Entry.QuarantineAction(); Action != "" && len(Entry.After.State) > 0 {
+		Restore, ok := StateRestorers[Action]
+		if !ok {
+			return fmt.Errorf("%s can't be rolled back", Entry.Action)
+		}
+		if err := Restore(sess, Resource, Entry.Before.State); err != nil {
+			return err
+		}
+	}
+	Removed, Restored := GetTagChanges(Entry.Before.Tags, Entry.After.Tags)
+	if len(Removed) > 0 {
+		Untagger, ok := ResourceUntaggers[Resource.Type]
+		if !ok {
+			Untagger = UntagResourceArn
+		}
+		if err := Untagger(sess, Resource, Removed); err != nil {
+			return err
+		}
+	}
+	if len(Restored) > 0 {
+		Tagger, ok := ResourceTaggers[Resource.Type]
+		if !ok {
+			Tagger = TagResourceArn
+		}
+		if err := Tagger(sess, Resource, Restored); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyRollback undoes the entries and returns the number that failed. The
+// rollback is journaled like any other write, under its own run.
+func ApplyRollback(sess *session.Session, Entries []AuditEntry, Journal string, Run string) int {
+	Failed := 0
+	for _, Entry := range Entries {
+		RegionSession := sess
+		if Entry.Region != "" {
+			RegionSession = sess.Copy(&aws.Config{Region: aws.String(Entry.Region)})
+		}
+		err := RollbackEntry(RegionSession, Entry)
+		Rollback := AuditEntry{
+			Time:    time.Now().UTC(),
+			Run:     Run,
+			Action:  "rollback:" + Entry.Action,
+			Type:    Entry.Type,
+			ID:      Entry.ID,
+			Arn:     Entry.Arn,
+			Region:  Entry.Region,
+			Before:  Entry.After,
+			After:   Entry.Before,
+			Reverts: Entry.Run,
+		}
+		if err != nil {
+			Failed++
+			Rollback.Error = err.Error()
+			fmt.Printf("Unable to roll back %s %s %v\n", Entry.Type, Entry.ID, err)
+		} else {
+			fmt.Printf("Rolled back %s %s\n", Entry.Type, Entry.ID)
+		}
+		if err := WriteAuditEntry(Journal, Rollback); err != nil {
+			fmt.Printf("Unable to write journal %s %v\n", Journal, err)
+		}
+	}
+	return Failed
+}
+
+// RestoreEC2InstanceState starts or stops the instance, instances in other
+// states are left alone.
+func RestoreEC2InstanceState(sess *session.Session, Resource Resource, State map[string]string) error {
+	svc := ec2.New(sess)
+	var err error
+	switch State["State"] {
+	case ec2.InstanceStateNameRunning, ec2.InstanceStateNamePending:
+		_, err = svc.StartInstances(&ec2.StartInstancesInput{
+			InstanceIds: aws.StringSlice([]string{Resource.ID}),
+		})
+	case ec2.InstanceStateNameStopped, ec2.InstanceStateNameStopping:
+		_, err = svc.StopInstances(&ec2.StopInstancesInput{
+			InstanceIds: aws.StringSlice([]string{Resource.ID}),
+		})
+	default:
+		err = fmt.Errorf("can't restore instance state %q", State["State"])
+	}
+	return err
+}
+
+// RestoreLambdaConcurrency sets the reserved concurrency back, or removes it
+// when the function had none.
+func RestoreLambdaConcurrency(sess *session.Session, Resource Resource, State map[string]string) error {
+	svc := lambda.New(sess)
+	Value, ok := State["ReservedConcurrency"]
+	if !ok {
+		_, err := svc.DeleteFunctionConcurrency(&lambda.DeleteFunctionConcurrencyInput{
+			FunctionName: aws.String(Resource.ID),
+		})
+		return err
+	}
+	Concurrency, err := strconv.ParseInt(Value, 10, 64)
+	if err != nil {
+		return err
+	}
+	_, err = svc.PutFunctionConcurrency(&lambda.PutFunctionConcurrencyInput{
+		FunctionName:                 aws.String(Resource.ID),
+		ReservedConcurrentExecutions: aws.Int64(Concurrency),
+	})
+	return err
+}
+
+// RestoreS3PublicAccess puts back the Block Public Access settings, or
+// removes the configuration when the bucket had none.
+func RestoreS3PublicAccess(sess *session.Session, Resource Resource, State map[string]string) error {
+	svc := s3.New(sess)
+	if len(State) == 0 {
+		_, err := svc.DeletePublicAccessBlock(&s3.DeletePublicAccessBlockInput{
+			Bucket: aws.String(Resource.ID),
+		})
+		return err
+	}
+	_, err := svc.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(Resource.ID),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(State["BlockPublicAcls"] == "true"),
+			BlockPublicPolicy:     aws.Bool(State["BlockPublicPolicy"] == "true"),
+			IgnorePublicAcls:      aws.Bool(State["IgnorePublicAcls"] == "true"),
+			RestrictPublicBuckets: aws.Bool(State["RestrictPublicBuckets"] == "true"),
+		},
+	})
+	return err
+}
+
+// RestoreVolume fails, EC2 can't restore a deleted volume. Recreate it from a
+// snapshot if there is one.
+func RestoreVolume(sess *session.Session, Resource Resource, State map[string]string) error {
+	return fmt.Errorf("deleted volumes can't be restored")
+}
+
+// UntagS3Bucket removes the keys from the bucket's tags, PutBucketTagging
+// replaces the whole tag set, and an empty tag set has to be deleted.
+func UntagS3Bucket(sess *session.Session, Resource Resource, Keys []string) error {
+	svc := s3.New(sess)
+	result, err := svc.GetBucketTagging(&s3.GetBucketTaggingInput{
+		Bucket: aws.String(Resource.ID),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchTagSet" {
+			return nil
+		}
+		return err
+	}
+	var TagSet []*s3.Tag
+	for _, Tag := range result.TagSet {
+		if !ContainsString(Keys, aws.StringValue(Tag.Key)) {
+			TagSet = append(TagSet, Tag)
+		}
+	}
+	if len(TagSet) == 0 {
+		_, err = svc.DeleteBucketTagging(&s3.DeleteBucketTaggingInput{
+			Bucket: aws.String(Resource.ID),
+		})
+		return err
+	}
+	_, err = svc.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket:  aws.String(Resource.ID),
+		Tagging: &s3.Tagging{TagSet: TagSet},
+	})
+	return err
+}
+
+func UntagEC2Resource(sess *session.Session, Resource Resource, Keys []string) error {
+	var TagList []*ec2.Tag
+	for _, Key := range Keys {
+		TagList = append(TagList, &ec2.Tag{Key: aws.String(Key)})
+	}
+	_, err := ec2.New(sess).DeleteTags(&ec2.DeleteTagsInput{
+		Resources: aws.StringSlice([]string{Resource.ID}),
+		Tags:      TagList,
+	})
+	return err
+}
+
+func UntagElbLoadBalancer(sess *session.Session, Resource Resource, Keys []string) error {
+	var TagList []*elb.TagKeyOnly
+	for _, Key := range Keys {
+		TagList = append(TagList, &elb.TagKeyOnly{Key: aws.String(Key)})
+	}
+	_, err := elb.New(sess).RemoveTags(&elb.RemoveTagsInput{
+		LoadBalancerNames: aws.StringSlice([]string{Resource.ID}),
+		Tags:              TagList,
+	})
+	return err
+}
+
+func UntagElbv2Resource(sess *session.Session, Resource Resource, Keys []string) error {
+	_, err := elbv2.New(sess).RemoveTags(&elbv2.RemoveTagsInput{
+		ResourceArns: aws.StringSlice([]string{Resource.Arn}),
+		TagKeys:      aws.StringSlice(Keys),
+	})
+	return err
+}
+
+func UntagLambdaFunction(sess *session.Session, Resource Resource, Keys []string) error {
+	_, err := lambda.New(sess).UntagResource(&lambda.UntagResourceInput{
+		Resource: aws.String(Resource.Arn),
+		TagKeys:  aws.StringSlice(Keys),
+	})
+	return err
+}
+
+func UntagResourceArn(sess *session.Session, Resource Resource, Keys []string) error {
+	if Resource.Arn == "" {
+		return fmt.Errorf("no untagger for %s and the resource has no ARN", Resource.Type)
+	}
+	result, err := resourcegroupstaggingapi.New(sess).UntagResources(&resourcegroupstaggingapi.UntagResourcesInput{
+		ResourceARNList: aws.StringSlice([]string{Resource.Arn}),
+		TagKeys:         aws.StringSlice(Keys),
+	})
+	if err != nil {
+		return err
+	}
+	if Failure, ok := result.FailedResourcesMap[Resource.Arn]; ok {
+		return fmt.Errorf("%s: %s", aws.StringValue(Failure.ErrorCode), aws.StringValue(Failure.ErrorMessage))
+	}
+	return nil
+}
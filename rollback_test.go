@@ -0,0 +1,116 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetTagChanges(t *testing.T) {
+	Cases := []struct {
+		Name     string
+		Before   map[string]string
+		After    map[string]string
+		Removed  []string
+		Restored map[string]string
+	}{
+		{"added", map[string]string{"Name": "a"}, map[string]string{"Name": "a", "Team": "x", "Owner": "y"}, []string{"Owner", "Team"}, map[string]string{}},
+		{"no tags before", nil, map[string]string{"Team": "x"}, []string{"Team"}, map[string]string{}},
+		{"changed", map[string]string{"Team": "old"}, map[string]string{"Team": "new"}, nil, map[string]string{"Team": "old"}},
+		{"unchanged", map[string]string{"Team": "x"}, map[string]string{"Team": "x"}, nil, map[string]string{}},
+		{"no tags recorded", nil, nil, nil, map[string]string{}},
+	}
+	for _, Case := range Cases {
+		Removed, Restored := GetTagChanges(Case.Before, Case.After)
+		if !reflect.DeepEqual(Removed, Case.Removed) || !reflect.DeepEqual(Restored, Case.Restored) {
+			t.Errorf("%s: got %v %v, want %v %v", Case.Name, Removed, Restored, Case.Removed, Case.Restored)
+		}
+	}
+}
+
+func TestGetRunEntries(t *testing.T) {
+	State := &MutationState{}
+	Journal := []AuditEntry{
+		{Run: "R1", Action: "tag", Type: "ec2", ID: "i-1", Before: State, After: State},
+		{Run: "R1", Action: "quarantine:stop", Type: "ec2", ID: "i-2", Before: State, After: State},
+		{Run: "R1", Action: "tag", Type: "s3", ID: "failed", Before: State, After: State, Error: "denied"},
+		{Run: "R1", Action: "quarantine:delete", Type: "ec2-volume", ID: "pending"},
+		{Run: "R2", Action: "tag", Type: "ec2", ID: "i-3", Before: State, After: State},
+		// R3 rolled back i-1 of R1 and failed on i-2.
+		{Run: "R3", Action: "rollback:tag", Type: "ec2", ID: "i-1", Before: State, After: State, Reverts: "R1"},
+		{Run: "R3", Action: "rollback:quarantine:stop", Type: "ec2", ID: "i-2", Before: State, After: State, Reverts: "R1", Error: "throttled"},
+	}
+	Cases := []struct {
+		Run string
+		IDs []string
+	}{
+		{"R1", []string{"i-2"}},
+		{"R2", []string{"i-3"}},
+		{"R3", []string{"i-1"}},
+		{"R9", nil},
+	}
+	for _, Case := range Cases {
+		var IDs []string
+		for _, Entry := range GetRunEntries(Journal, Case.Run) {
+			IDs = append(IDs, Entry.ID)
+		}
+		if !reflect.DeepEqual(IDs, Case.IDs) {
+			t.Errorf("run %s: got %v, want %v", Case.Run, IDs, Case.IDs)
+		}
+	}
+}
+
+func TestGetRunEntriesLatestFirst(t *testing.T) {
+	State := &MutationState{}
+	Journal := []AuditEntry{
+		{Run: "R1", Action: "tag", Type: "ec2", ID: "first", Before: State, After: State},
+		{Run: "R1", Action: "tag", Type: "ec2", ID: "second", Before: State, After: State},
+	}
+	Entries := GetRunEntries(Journal, "R1")
+	if len(Entries) != 2 || Entries[0].ID != "second" || Entries[1].ID != "first" {
+		t.Errorf("got %v, want second then first", Entries)
+	}
+}
+
+func TestQuarantineAction(t *testing.T) {
+	Cases := map[string]string{
+		"tag":                               "",
+		"quarantine:stop":                   "stop",
+		"rollback:quarantine:disable":       "disable",
+		"rollback:rollback:quarantine:stop": "stop",
+		"rollback:tag":                      "",
+	}
+	for Action, Expected := range Cases {
+		if Got := (AuditEntry{Action: Action}).QuarantineAction(); Got != Expected {
+			t.Errorf("%s: got %q, want %q", Action, Got, Expected)
+		}
+	}
+}
+
+func TestGetJournal(t *testing.T) {
+	Dir := t.TempDir()
+	Path := filepath.Join(Dir, "remediation.log")
+	Entry := AuditEntry{Run: "R1", Action: "tag", Type: "ec2", ID: "i-1", Before: &MutationState{}, After: &MutationState{Tags: map[string]string{"Team": "x"}}}
+	if err := WriteAuditEntry(Path, Entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAuditEntry(Path, Entry); err != nil {
+		t.Fatal(err)
+	}
+	Entries, err := GetJournal(Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(Entries) != 2 || Entries[0].After.Tags["Team"] != "x" {
+		t.Errorf("got %+v", Entries)
+	}
+
+	Broken := filepath.Join(Dir, "broken.log")
+	if err := ioutil.WriteFile(Broken, []byte("{\"run\":\"R1\"}\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetJournal(Broken); err == nil {
+		t.Error("broken journal: expected an error")
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// ResourceScanners maps the resource identifiers used in policy.yaml to the
+// Init function that scans that resource type.
+var ResourceScanners = map[string]func(*Policy, *session.Session){
+	"s3":                               S3Init,
+	"ec2":                              EC2Init,
+	"elb":                              ELBInit,
+	"elb-targetgroup":                  ElbTargetGroupInit,
+	"elbv2":                            Elbv2Init,
+	"elbv2-listener":                   Elbv2ListenerInit,
+	"elbv2-listener-rule":              Elbv2ListenerRuleInit,
+	"lambda-functions":                 LambdaInit,
+	"rds":                              RDSInit,
+	"route53-hostedzone":               Route53Init,
+	"sqs":                              SQSInit,
+	"workspaces":                       WorkspacesInit,
+	"ec2-eip":                          ElasticIpInit,
+	"ec2-image":                        AmiInit,
+	"ec2-internetgateway":              InternetGatewayInit,
+	"ec2-natgateway":                   NatGatewayInit,
+	"ec2-networkacl":                   NetworkAclInit,
+	"ec2-reservedinstances":            ReservedInstanceInit,
+	"ec2-routetable":                   RouteTableInit,
+	"ec2-securitygroup":                SecurityGroupInit,
+	"ec2-snapshot":                     EC2SnapShotInit,
+	"ec2-volume":                       EC2VolumeInit,
+	"ec2-networkinterface":             NetworkInterfaceInit,
+	"eks-cluster":                      EKSClusterInit,
+	"eks-nodegroup":                    EKSNodegroupInit,
+	"eks-fargateprofile":               EKSFargateProfileInit,
+	"ecs-cluster":                      ECSClusterInit,
+	"ecs-service":                      ECSServiceInit,
+	"ecs-taskdefinition":               ECSTaskDefinitionInit,
+	"ecr-repository":                   ECRRepositoryInit,
+	"dynamodb":                         DynamoDBInit,
+	"efs":                              EFSInit,
+	"elasticache-cluster":              ElastiCacheInit,
+	"opensearch-domain":                OpenSearchInit,
+	"redshift-cluster":                 RedshiftInit,
+	"aws-kms-key":                      KMSKeyInit,
+	"aws-acm-certificate":              ACMCertificateInit,
+	"secretsmanager-secret":            SecretsManagerInit,
+	"iam-role":                         IAMRoleInit,
+	"iam-user":                         IAMUserInit,
+	"iam-policy":                       IAMPolicyInit,
+	"sns-topic":                        SNSTopicInit,
+	"eventbridge-bus":                  EventBusInit,
+	"eventbridge-rule":                 EventRuleInit,
+	"kinesis-stream":                   KinesisStreamInit,
+	"firehose-deliverystream":          FirehoseInit,
+	"cloudfront:distribution":          CloudFrontDistributionInit,
+	"cloudfront:streamingdistribution": CloudFrontStreamingDistributionInit,
+	"cloudtrail":                       CloudTrailInit,
+	"autoscaling-group":                AutoScalingGroupInit,
+	"ec2-launchtemplate":               LaunchTemplateInit,
+	"stepfunctions-statemachine":       StateMachineInit,
+	"apigateway-restapi":               RestApiInit,
+	"apigatewayv2-api":                 HttpApiInit,
+	"apigateway-stage":                 ApiStageInit,
+	"glue-job":                         GlueJobInit,
+	"glue-trigger":                     GlueTriggerInit,
+	"glue-crawler":                     GlueCrawlerInit,
+	"glue-database":                    GlueDatabaseInit,
+	"cloudwatch-alarm":                 CloudWatchAlarmInit,
+	"cloudwatch-loggroup":              LogGroupInit,
+	"cloudwatch-metricstream":          MetricStreamInit,
+}
+
+// CurrentRegion is the region being scanned, reported as each resource's
+// region.
+var CurrentRegion string
+
+func RunPolicy(PolicyObject *Policy, sess *session.Session) {
+	CurrentRegion = aws.StringValue(sess.Config.Region)
+	// The account ID is needed by when conditions on accounts.
+	if _, err := GetAccountIdentity(sess); err != nil {
+		fmt.Printf("Unable to load account identity %v\n", err)
+	}
+	for _, Rule := range PolicyObject.Policy {
+		// Scope the policy to this rule so GetPolicyKeys returns its keys.
+		RulePolicy := &Policy{Policy: []PolicyRule{Rule}}
+		for _, ResourceName := range Rule.Resources {
+			Init, ok := ResourceScanners[ResourceName]
+			if !ok {
+				fmt.Println("No scanner for resource:", ResourceName)
+				continue
+			}
+			fmt.Println("\n\nPolicy:", Rule.Name, "Resource:", ResourceName)
+			Init(RulePolicy, sess)
+		}
+	}
+}
+
+// GlobalResourceCache holds resources of global services such as IAM, which
+// are the same in every region. They are listed once per account and shared by
+// every rule that lists the resource.
+var GlobalResourceCache = make(map[string][]Resource)
+
+func GetGlobalResources(ResourceName string, List func() []Resource) []Resource {
+	if Resources, ok := GlobalResourceCache[ResourceName]; ok {
+		return Resources
+	}
+	Resources := List()
+	GlobalResourceCache[ResourceName] = Resources
+	return Resources
+}
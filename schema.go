@@ -0,0 +1,115 @@
+package main
+
+import (
+	"sort"
+)
+
+// GetPolicySchema returns the JSON Schema of policy files, used by editors
+// for completion and checks. policy.schema.json is generated from it with
+// tag-police policy schema.
+func GetPolicySchema() map[string]interface{} {
+	var ResourceNames []string
+	for ResourceName := range ResourceScanners {
+		ResourceNames = append(ResourceNames, ResourceName)
+	}
+	sort.Strings(ResourceNames)
+	ArrayOf := func(Items map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "array", "items": Items}
+	}
+	String := map[string]interface{}{"type": "string"}
+	Severity := map[string]interface{}{"type": "string", "enum": Severities}
+	ResourceMatch := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"name":       String,
+			"arn":        String,
+			"tags":       map[string]interface{}{"type": "object", "additionalProperties": String},
+			"attributes": map[string]interface{}{"type": "object", "additionalProperties": String},
+			"types":      ArrayOf(String),
+			"regions":    ArrayOf(String),
+			"accounts":   ArrayOf(String),
+		},
+	}
+	PolicyKey := map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "string", "minLength": 1},
+			map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []string{"key"},
+				"properties": map[string]interface{}{
+					"key":      map[string]interface{}{"type": "string", "minLength": 1},
+					"when":     ResourceMatch,
+					"severity": Severity,
+					"values":   ArrayOf(String),
+					"aliases":  ArrayOf(String),
+					"default":  String,
+				},
+			},
+		},
+	}
+	PolicyRule := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"name"},
+		"properties": map[string]interface{}{
+			"name":           String,
+			"extend":         String,
+			"abstract":       map[string]interface{}{"type": "boolean"},
+			"resources":      map[string]interface{}{"type": "array", "uniqueItems": true, "items": map[string]interface{}{"type": "string", "enum": ResourceNames}},
+			"caseinsenstive": map[string]interface{}{"type": "boolean"},
+			"keys":           ArrayOf(PolicyKey),
+			"checks": ArrayOf(map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []string{"name", "expression"},
+				"properties": map[string]interface{}{
+					"name":       String,
+					"expression": String,
+					"message":    String,
+					"severity":   Severity,
+				},
+			}),
+			"instancestates": ArrayOf(map[string]interface{}{"type": "string", "enum": InstanceStates}),
+			"selector": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"include": ArrayOf(ResourceMatch),
+					"exclude": ArrayOf(ResourceMatch),
+				},
+			},
+			"suppression": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"tag":      String,
+					"disabled": ArrayOf(String),
+				},
+			},
+			"graceperiod": map[string]interface{}{"type": "string", "pattern": "^([0-9]+d|([0-9.]+(ns|us|µs|ms|s|m|h))+)$"},
+			"severity":    Severity,
+			"mode":        map[string]interface{}{"type": "string", "enum": Modes},
+			"inherit":     map[string]interface{}{"type": "string", "enum": []string{InheritSatisfy, InheritCopy}},
+			"quarantine": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"after":   map[string]interface{}{"type": "string"},
+					"actions": map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string", "enum": GetQuarantineActionNames()}},
+				},
+			},
+		},
+	}
+	return map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "tag-police policy",
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"include": ArrayOf(String),
+			"policy":  ArrayOf(PolicyRule),
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+func SecretsManagerFinder(SecretList []*secretsmanager.SecretListEntry, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Secret := range SecretList {
+		// ListSecrets already returns the secret's tags.
+		Tags := make(map[string]string)
+		for _, Tag := range Secret.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "secretsmanager-secret",
+			ID:        aws.StringValue(Secret.Name),
+			Arn:       aws.StringValue(Secret.ARN),
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(Secret.CreatedDate),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func SecretsManagerInit(PolicyObject *Policy, sess *session.Session) {
+	svc := secretsmanager.New(sess)
+	var Secrets []*secretsmanager.SecretListEntry
+	err := svc.ListSecretsPages(&secretsmanager.ListSecretsInput{}, func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+		Secrets = append(Secrets, page.SecretList...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load Secrets %v\n", err)
+		return
+	}
+	if len(Secrets) == 0 {
+		fmt.Printf("No Secrets for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := SecretsManagerFinder(Secrets, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
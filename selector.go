@@ -0,0 +1,124 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// Selector narrows the resources a policy applies to. A resource is checked
+// when it matches at least one Include entry, or Include is empty, and none of
+// the Exclude entries.
+//
+//	selector:
+//	  include:
+//	  - name: "prod-*"
+//	  - tags:
+//	      Environment: prod
+//	  exclude:
+//	  - tags:
+//	      "aws:cloudformation:*": "*"
+type Selector struct {
+	Include []ResourceMatch `yaml:"include,omitempty"`
+	Exclude []ResourceMatch `yaml:"exclude,omitempty"`
+}
+
+// ResourceMatch matches a resource when every field that is set matches.
+// Values are patterns where * matches any run of characters. Tags maps a tag
+// key pattern to a value pattern, Attributes maps an attribute name reported by
+// the scanner (e.g. VpcId) to a value pattern. Types, Regions and Accounts
+// match when any of their patterns matches the resource identifier, region or
+// account ID.
+type ResourceMatch struct {
+	Name       string            `yaml:"name,omitempty"`
+	Arn        string            `yaml:"arn,omitempty"`
+	Tags       map[string]string `yaml:"tags,omitempty"`
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+	Types      []string          `yaml:"types,omitempty"`
+	Regions    []string          `yaml:"regions,omitempty"`
+	Accounts   []string          `yaml:"accounts,omitempty"`
+}
+
+func MatchPattern(Pattern, Value string) bool {
+	Expression := "^" + strings.ReplaceAll(regexp.QuoteMeta(Pattern), `\*`, ".*") + "$"
+	return regexp.MustCompile(Expression).MatchString(Value)
+}
+
+func MatchAnyPattern(Patterns []string, Value string) bool {
+	for _, Pattern := range Patterns {
+		if MatchPattern(Pattern, Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty reports whether no field is set, so the match matches any resource.
+func (Match ResourceMatch) IsEmpty() bool {
+	return Match.Name == "" && Match.Arn == "" && len(Match.Tags) == 0 && len(Match.Attributes) == 0 &&
+		len(Match.Types) == 0 && len(Match.Regions) == 0 && len(Match.Accounts) == 0
+}
+
+func (Match ResourceMatch) Matches(Resource Resource) bool {
+	if Match.Name != "" && !MatchPattern(Match.Name, Resource.ID) {
+		return false
+	}
+	if Match.Arn != "" && !MatchPattern(Match.Arn, Resource.Arn) {
+		return false
+	}
+	if len(Match.Types) > 0 && !MatchAnyPattern(Match.Types, Resource.Type) {
+		return false
+	}
+	if len(Match.Regions) > 0 && !MatchAnyPattern(Match.Regions, Resource.Region) {
+		return false
+	}
+	if len(Match.Accounts) > 0 && !MatchAnyPattern(Match.Accounts, Resource.Account) {
+		return false
+	}
+	for KeyPattern, ValuePattern := range Match.Tags {
+		TagMatched := false
+		for Key, Value := range Resource.Tags {
+			if MatchPattern(KeyPattern, Key) && MatchPattern(ValuePattern, Value) {
+				TagMatched = true
+				break
+			}
+		}
+		if !TagMatched {
+			return false
+		}
+	}
+	for Name, ValuePattern := range Match.Attributes {
+		Value, ok := Resource.Attributes[Name]
+		if !ok || !MatchPattern(ValuePattern, Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (Selector Selector) Selects(Resource Resource) bool {
+	for _, Match := range Selector.Exclude {
+		if Match.Matches(Resource) {
+			return false
+		}
+	}
+	if len(Selector.Include) == 0 {
+		return true
+	}
+	for _, Match := range Selector.Include {
+		if Match.Matches(Resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func GetPartition(Region string) string {
+	// Partition of the region, e.g. "aws" or "aws-cn", used to build ARNs.
+	Partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), Region)
+	if !ok {
+		return endpoints.AwsPartitionID
+	}
+	return Partition.ID()
+}
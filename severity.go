@@ -0,0 +1,71 @@
+package main
+
+// Severity levels, from lowest to highest.
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+var Severities = []string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// DefaultSeverity applies to policies and keys without a severity.
+const DefaultSeverity = SeverityMedium
+
+// Enforcement modes. Violations of audit policies are only listed in the
+// report, warn policies also flag them while scanning, and violations of
+// enforce policies fail the run.
+const (
+	ModeAudit   = "audit"
+	ModeWarn    = "warn"
+	ModeEnforce = "enforce"
+)
+
+var Modes = []string{ModeAudit, ModeWarn, ModeEnforce}
+
+// DefaultMode is warn so policies without a mode keep passing the run.
+const DefaultMode = ModeWarn
+
+// SeverityRank orders severities, it is -1 for an unknown severity.
+func SeverityRank(Severity string) int {
+	for Rank, Level := range Severities {
+		if Level == Severity {
+			return Rank
+		}
+	}
+	return -1
+}
+
+func (Rule PolicyRule) GetSeverity() string {
+	if Rule.Severity == "" {
+		return DefaultSeverity
+	}
+	return Rule.Severity
+}
+
+// GetKeySeverity is the key's own severity, or the policy's when it has none.
+func (Rule PolicyRule) GetKeySeverity(PolicyTag PolicyKey) string {
+	if PolicyTag.Severity == "" {
+		return Rule.GetSeverity()
+	}
+	return PolicyTag.Severity
+}
+
+func (Rule PolicyRule) GetMode() string {
+	if Rule.Mode == "" {
+		return DefaultMode
+	}
+	return Rule.Mode
+}
+
+// GetExitCode is 1 when an enforced violation is at least FailSeverity.
+func GetExitCode(FailSeverity string) int {
+	for _, Finding := range GetFindings(StatusViolation) {
+		if Finding.Mode == ModeEnforce && SeverityRank(Finding.Severity) >= SeverityRank(FailSeverity) {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestGetExitCode(t *testing.T) {
+	defer func(Saved []Finding) { Report = Saved }(Report)
+	Report = []Finding{
+		{Status: StatusViolation, Severity: SeverityCritical, Mode: ModeAudit},
+		{Status: StatusViolation, Severity: SeverityCritical, Mode: ModeWarn},
+		{Status: StatusExempt, Severity: SeverityCritical, Mode: ModeEnforce},
+		{Status: StatusViolation, Severity: SeverityMedium, Mode: ModeEnforce},
+	}
+	Cases := []struct {
+		FailSeverity string
+		ExitCode     int
+	}{
+		{SeverityInfo, 1},
+		{SeverityMedium, 1},
+		{SeverityHigh, 0},
+		{SeverityCritical, 0},
+	}
+	for _, Case := range Cases {
+		if ExitCode := GetExitCode(Case.FailSeverity); ExitCode != Case.ExitCode {
+			t.Errorf("%s: got %d, want %d", Case.FailSeverity, ExitCode, Case.ExitCode)
+		}
+	}
+	Report = nil
+	if ExitCode := GetExitCode(SeverityInfo); ExitCode != 0 {
+		t.Errorf("no findings: got %d, want 0", ExitCode)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+func SNSTopicFinder(svc *sns.SNS, TopicList []*sns.Topic, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, Topic := range TopicList {
+		result, err := svc.ListTagsForResource(&sns.ListTagsForResourceInput{
+			ResourceArn: Topic.TopicArn,
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *Topic.TopicArn, err)
+			continue
+		}
+		Tags := make(map[string]string)
+		for _, Tag := range result.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type: "sns-topic",
+			ID:   *Topic.TopicArn,
+			Arn:  *Topic.TopicArn,
+			Tags: Tags,
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func SNSTopicInit(PolicyObject *Policy, sess *session.Session) {
+	svc := sns.New(sess)
+	var Topics []*sns.Topic
+	err := svc.ListTopicsPages(&sns.ListTopicsInput{}, func(page *sns.ListTopicsOutput, lastPage bool) bool {
+		Topics = append(Topics, page.Topics...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load SNS Topics %v\n", err)
+		return
+	}
+	if len(Topics) == 0 {
+		fmt.Printf("No SNS Topics for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := SNSTopicFinder(svc, Topics, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sfn"
+)
+
+func StateMachineFinder(svc *sfn.SFN, StateMachineList []*sfn.StateMachineListItem, Rule PolicyRule) ([]string, []string) {
+	var Resources []Resource
+	for _, StateMachine := range StateMachineList {
+		result, err := svc.ListTagsForResource(&sfn.ListTagsForResourceInput{
+			ResourceArn: StateMachine.StateMachineArn,
+		})
+		if err != nil {
+			fmt.Printf("Unable to load tags for %s %v\n", *StateMachine.Name, err)
+			continue
+		}
+		Tags := make(map[string]string)
+		for _, Tag := range result.Tags {
+			Tags[aws.StringValue(Tag.Key)] = aws.StringValue(Tag.Value)
+		}
+		Resources = append(Resources, Resource{
+			Type:      "stepfunctions-statemachine",
+			ID:        *StateMachine.Name,
+			Arn:       *StateMachine.StateMachineArn,
+			Tags:      Tags,
+			CreatedAt: aws.TimeValue(StateMachine.CreationDate),
+		})
+	}
+	return ResourceFinder(Resources, Rule)
+}
+
+func StateMachineInit(PolicyObject *Policy, sess *session.Session) {
+	svc := sfn.New(sess)
+	var StateMachines []*sfn.StateMachineListItem
+	err := svc.ListStateMachinesPages(&sfn.ListStateMachinesInput{}, func(page *sfn.ListStateMachinesOutput, lastPage bool) bool {
+		StateMachines = append(StateMachines, page.StateMachines...)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("Unable to load State Machines %v\n", err)
+		return
+	}
+	if len(StateMachines) == 0 {
+		fmt.Printf("No State Machines for %s region\n", *svc.Config.Region)
+		return
+	}
+	Tagged, UnTagged := StateMachineFinder(svc, StateMachines, GetPolicyRule(PolicyObject))
+	fmt.Println("\n\n\n\nFinal Tagged:", Tagged)
+	fmt.Println("Final UnTagged:", UnTagged)
+}
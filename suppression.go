@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultSuppressionTag is the tag a resource carries to opt itself out of
+// the policy checks, e.g. tag-police:ignore=legacy vendor box until 2026-12-31.
+const DefaultSuppressionTag = "tag-police:ignore"
+
+// Suppression configures self-suppression through a resource tag. The tag
+// value is the reason, optionally followed by " until YYYY-MM-DD" after which
+// the resource is checked again. Disabled lists resource identifiers that
+// cannot suppress themselves.
+//
+//	suppression:
+//	  tag: "tag-police:ignore"
+//	  disabled:
+//	  - iam-role
+type Suppression struct {
+	Tag      string   `yaml:"tag,omitempty"`
+	Disabled []string `yaml:"disabled,omitempty"`
+}
+
+func (Suppression Suppression) GetTag() string {
+	if Suppression.Tag == "" {
+		return DefaultSuppressionTag
+	}
+	return Suppression.Tag
+}
+
+// GetSuppression returns the suppression reason and expiry date from the
+// resource's suppression tag. ok is false when the resource has no tag or its
+// type may not suppress itself.
+func (Suppression Suppression) GetSuppression(Resource Resource) (Reason string, Until string, ok bool) {
+	if ContainsString(Suppression.Disabled, Resource.Type) {
+		return "", "", false
+	}
+	Value, ok := Resource.Tags[Suppression.GetTag()]
+	if !ok {
+		return "", "", false
+	}
+	Reason = Value
+	if index := strings.LastIndex(Value, " until "); index != -1 {
+		Date := strings.TrimSpace(Value[index+len(" until "):])
+		if _, err := time.Parse(ExemptionDateLayout, Date); err == nil {
+			Reason, Until = strings.TrimSpace(Value[:index]), Date
+		}
+	}
+	return Reason, Until, true
+}
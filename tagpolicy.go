@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OrgResourceTypes maps tag-police resource identifiers to the resource types
+// AWS Organizations tag policies use in enforced_for.
+var OrgResourceTypes = map[string]string{
+	"s3":                               "s3:bucket",
+	"ec2":                              "ec2:instance",
+	"elb":                              "elasticloadbalancing:loadbalancer",
+	"elb-targetgroup":                  "elasticloadbalancing:targetgroup",
+	"elbv2":                            "elasticloadbalancing:loadbalancer",
+	"elbv2-listener":                   "elasticloadbalancing:listener",
+	"elbv2-listener-rule":              "elasticloadbalancing:listener-rule",
+	"lambda-functions":                 "lambda:function",
+	"rds":                              "rds:db",
+	"route53-hostedzone":               "route53:hostedzone",
+	"sqs":                              "sqs:queue",
+	"workspaces":                       "workspaces:workspace",
+	"ec2-eip":                          "ec2:elastic-ip",
+	"ec2-image":                        "ec2:image",
+	"ec2-internetgateway":              "ec2:internet-gateway",
+	"ec2-natgateway":                   "ec2:natgateway",
+	"ec2-networkacl":                   "ec2:network-acl",
+	"ec2-reservedinstances":            "ec2:reserved-instances",
+	"ec2-routetable":                   "ec2:route-table",
+	"ec2-securitygroup":                "ec2:security-group",
+	"ec2-snapshot":                     "ec2:snapshot",
+	"ec2-volume":                       "ec2:volume",
+	"ec2-networkinterface":             "ec2:network-interface",
+	"ec2-launchtemplate":               "ec2:launch-template",
+	"eks-cluster":                      "eks:cluster",
+	"eks-nodegroup":                    "eks:nodegroup",
+	"eks-fargateprofile":               "eks:fargateprofile",
+	"ecs-cluster":                      "ecs:cluster",
+	"ecs-service":                      "ecs:service",
+	"ecs-taskdefinition":               "ecs:task-definition",
+	"ecr-repository":                   "ecr:repository",
+	"dynamodb":                         "dynamodb:table",
+	"efs":                              "elasticfilesystem:file-system",
+	"elasticache-cluster":              "elasticache:cluster",
+	"opensearch-domain":                "es:domain",
+	"redshift-cluster":                 "redshift:cluster",
+	"aws-kms-key":                      "kms:key",
+	"aws-acm-certificate":              "acm:certificate",
+	"secretsmanager-secret":            "secretsmanager:secret",
+	"iam-role":                         "iam:role",
+	"iam-user":                         "iam:user",
+	"iam-policy":                       "iam:policy",
+	"sns-topic":                        "sns:topic",
+	"eventbridge-bus":                  "events:event-bus",
+	"eventbridge-rule":                 "events:rule",
+	"kinesis-stream":                   "kinesis:stream",
+	"firehose-deliverystream":          "firehose:deliverystream",
+	"cloudfront:distribution":          "cloudfront:distribution",
+	"cloudfront:streamingdistribution": "cloudfront:streaming-distribution",
+	"cloudtrail":                       "cloudtrail:trail",
+	"autoscaling-group":                "autoscaling:autoScalingGroup",
+	"stepfunctions-statemachine":       "states:stateMachine",
+	"apigateway-restapi":               "apigateway:restapis",
+	"apigatewayv2-api":                 "apigateway:apis",
+	"apigateway-stage":                 "apigateway:stages",
+	"glue-job":                         "glue:job",
+	"glue-trigger":                     "glue:trigger",
+	"glue-crawler":                     "glue:crawler",
+	"glue-database":                    "glue:database",
+	"cloudwatch-alarm":                 "cloudwatch:alarm",
+	"cloudwatch-loggroup":              "logs:log-group",
+	"cloudwatch-metricstream":          "cloudwatch:metric-stream",
+}
+
+// OrgTagPolicy is an AWS Organizations tag policy document:
+//
+//	{
+//	  "tags": {
+//	    "costcenter": {
+//	      "tag_key": {"@@assign": "CostCenter"},
+//	      "tag_value": {"@@assign": ["100", "200", "300*"]},
+//	      "enforced_for": {"@@assign": ["ec2:instance", "s3:ALL_SUPPORTED"]}
+//	    }
+//	  }
+//	}
+type OrgTagPolicy struct {
+	Tags map[string]OrgTagPolicyTag `json:"tags"`
+}
+
+type OrgTagPolicyTag struct {
+	TagKey      OrgAssign      `json:"tag_key"`
+	TagValue    *OrgAssignList `json:"tag_value,omitempty"`
+	EnforcedFor *OrgAssignList `json:"enforced_for,omitempty"`
+}
+
+// OrgAssign and OrgAssignList are tag policy values. Only @@assign is
+// imported, Ignored lists the other inheritance operators the value used,
+// e.g. @@append, which only make sense within an organization's policies.
+type OrgAssign struct {
+	Assign  string   `json:"@@assign"`
+	Ignored []string `json:"-"`
+}
+
+type OrgAssignList struct {
+	Assign  []string `json:"@@assign"`
+	Ignored []string `json:"-"`
+}
+
+// OrgAllSupported in enforced_for, e.g. ec2:ALL_SUPPORTED, stands for every
+// resource type of the service.
+const OrgAllSupported = "ALL_SUPPORTED"
+
+func (Value *OrgAssign) UnmarshalJSON(Data []byte) error {
+	var err error
+	Value.Ignored, err = UnmarshalOrgOperators(Data, &Value.Assign)
+	return err
+}
+
+func (Value *OrgAssignList) UnmarshalJSON(Data []byte) error {
+	var err error
+	Value.Ignored, err = UnmarshalOrgOperators(Data, &Value.Assign)
+	return err
+}
+
+// UnmarshalOrgOperators decodes @@assign into Assign and returns the other
+// operators. @@operators_allowed_for_child_policies only restricts child
+// policies and isn't returned.
+func UnmarshalOrgOperators(Data []byte, Assign interface{}) ([]string, error) {
+	var Operators map[string]json.RawMessage
+	if err := json.Unmarshal(Data, &Operators); err != nil {
+		return nil, err
+	}
+	var Ignored []string
+	for Operator, Value := range Operators {
+		switch Operator {
+		case "@@assign":
+			if err := json.Unmarshal(Value, Assign); err != nil {
+				return nil, fmt.Errorf("%s: %v", Operator, err)
+			}
+		case "@@operators_allowed_for_child_policies":
+		default:
+			Ignored = append(Ignored, Operator)
+		}
+	}
+	sort.Strings(Ignored)
+	return Ignored, nil
+}
+
+// GetOrgResourceMatches returns the resources a type in enforced_for stands
+// for, all of the service's for <service>:ALL_SUPPORTED.
+func GetOrgResourceMatches(ResourceType string) []string {
+	var ResourceNames []string
+	Service, Type, _ := strings.Cut(ResourceType, ":")
+	for _, ResourceName := range GetOrgResourceNames() {
+		OrgType := OrgResourceTypes[ResourceName]
+		if OrgType == ResourceType || (Type == OrgAllSupported && strings.HasPrefix(OrgType, Service+":")) {
+			ResourceNames = append(ResourceNames, ResourceName)
+		}
+	}
+	return ResourceNames
+}
+
+func GetOrgResourceNames() []string {
+	var ResourceNames []string
+	for ResourceName := range OrgResourceTypes {
+		ResourceNames = append(ResourceNames, ResourceName)
+	}
+	sort.Strings(ResourceNames)
+	return ResourceNames
+}
+
+// ImportOrgTagPolicy converts a tag policy to one tag-police policy per tag,
+// named org-<tag>. The tag's key capitalization is required, its values are
+// the allowed values and enforced_for picks the resources, every resource when
+// it isn't set. The warnings list what has no tag-police equivalent.
+func ImportOrgTagPolicy(Document OrgTagPolicy) ([]PolicyRule, []string) {
+	var Rules []PolicyRule
+	var Warnings []string
+	var TagNames []string
+	for TagName := range Document.Tags {
+		TagNames = append(TagNames, TagName)
+	}
+	sort.Strings(TagNames)
+	for _, TagName := range TagNames {
+		Tag := Document.Tags[TagName]
+		for _, Operator := range Tag.TagKey.Ignored {
+			Warnings = append(Warnings, fmt.Sprintf("tag %s: tag_key: %s ignored, only @@assign is imported", TagName, Operator))
+		}
+		PolicyTag := PolicyKey{Key: Tag.TagKey.Assign}
+		if PolicyTag.Key == "" {
+			PolicyTag.Key = TagName
+		}
+		if Tag.TagValue != nil {
+			PolicyTag.Values = Tag.TagValue.Assign
+			for _, Operator := range Tag.TagValue.Ignored {
+				Warnings = append(Warnings, fmt.Sprintf("tag %s: tag_value: %s ignored, only @@assign is imported", TagName, Operator))
+			}
+		}
+		Rule := PolicyRule{
+			Name: "org-" + TagName,
+			Keys: []PolicyKey{PolicyTag},
+		}
+		if Tag.EnforcedFor == nil {
+			Rule.Resources = GetOrgResourceNames()
+		} else {
+			for _, Operator := range Tag.EnforcedFor.Ignored {
+				Warnings = append(Warnings, fmt.Sprintf("tag %s: enforced_for: %s ignored, only @@assign is imported", TagName, Operator))
+			}
+			for _, ResourceType := range Tag.EnforcedFor.Assign {
+				Matches := GetOrgResourceMatches(ResourceType)
+				for _, ResourceName := range Matches {
+					if !ContainsString(Rule.Resources, ResourceName) {
+						Rule.Resources = append(Rule.Resources, ResourceName)
+					}
+				}
+				if len(Matches) == 0 {
+					Warnings = append(Warnings, fmt.Sprintf("tag %s: no scanner for resource type %s", TagName, ResourceType))
+				}
+			}
+		}
+		if len(Rule.Resources) == 0 {
+			Warnings = append(Warnings, fmt.Sprintf("tag %s: skipped, none of its resource types are scanned", TagName))
+			continue
+		}
+		Rules = append(Rules, Rule)
+	}
+	return Rules, Warnings
+}
+
+// ExportOrgTagPolicy converts tag-police policies to a tag policy. A key in
+// several policies is enforced for the resources of all of them and allows
+// the values of all of them. enforced_for is left out for keys checked on
+// every resource. The warnings list what the tag policy cannot express.
+func ExportOrgTagPolicy(Rules []PolicyRule) (OrgTagPolicy, []string) {
+	Document := OrgTagPolicy{Tags: make(map[string]OrgTagPolicyTag)}
+	var Warnings []string
+	ResourceTypes := make(map[string][]string)
+	AllResources := make(map[string]bool)
+	for _, Rule := range Rules {
+		if Rule.IsCaseInsensitive() {
+			Warnings = append(Warnings, fmt.Sprintf("policy %s: tag policies always check key capitalization", Rule.Name))
+		}
+		for _, PolicyTag := range Rule.Keys {
+			TagName := strings.ToLower(PolicyTag.Key)
+			Tag := Document.Tags[TagName]
+			if Tag.TagKey.Assign != "" && Tag.TagKey.Assign != PolicyTag.Key {
+				Warnings = append(Warnings, fmt.Sprintf("policy %s: key %s: capitalization differs from %s", Rule.Name, PolicyTag.Key, Tag.TagKey.Assign))
+			}
+			if Tag.TagKey.Assign == "" {
+				Tag.TagKey.Assign = PolicyTag.Key
+			}
+			if len(PolicyTag.Aliases) > 0 {
+				Warnings = append(Warnings, fmt.Sprintf("policy %s: key %s: aliases are not exported", Rule.Name, PolicyTag.Key))
+			}
+			if !PolicyTag.When.IsEmpty() {
+				Warnings = append(Warnings, fmt.Sprintf("policy %s: key %s: when conditions are not exported", Rule.Name, PolicyTag.Key))
+			}
+			if len(PolicyTag.Values) > 0 {
+				if Tag.TagValue == nil {
+					Tag.TagValue = &OrgAssignList{}
+				}
+				for _, Value := range PolicyTag.Values {
+					if !ContainsString(Tag.TagValue.Assign, Value) {
+						Tag.TagValue.Assign = append(Tag.TagValue.Assign, Value)
+					}
+				}
+			}
+			if len(Rule.Resources) == len(OrgResourceTypes) {
+				AllResources[TagName] = true
+			}
+			for _, ResourceName := range Rule.Resources {
+				ResourceType, ok := OrgResourceTypes[ResourceName]
+				if !ok {
+					Warnings = append(Warnings, fmt.Sprintf("policy %s: no tag policy resource type for %s", Rule.Name, ResourceName))
+					continue
+				}
+				if !ContainsString(ResourceTypes[TagName], ResourceType) {
+					ResourceTypes[TagName] = append(ResourceTypes[TagName], ResourceType)
+				}
+			}
+			Document.Tags[TagName] = Tag
+		}
+		if len(Rule.Checks) > 0 {
+			Warnings = append(Warnings, fmt.Sprintf("policy %s: checks are not exported", Rule.Name))
+		}
+		if len(Rule.Selector.Include) > 0 || len(Rule.Selector.Exclude) > 0 {
+			Warnings = append(Warnings, fmt.Sprintf("policy %s: selectors are not exported", Rule.Name))
+		}
+	}
+	for TagName, Tag := range Document.Tags {
+		if !AllResources[TagName] && len(ResourceTypes[TagName]) > 0 {
+			sort.Strings(ResourceTypes[TagName])
+			Tag.EnforcedFor = &OrgAssignList{Assign: ResourceTypes[TagName]}
+			Document.Tags[TagName] = Tag
+		}
+	}
+	return Document, Warnings
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestImportOrgTagPolicy(t *testing.T) {
+	Document := `{"tags": {
+		"costcenter": {
+			"tag_key": {"@@assign": "CostCenter", "@@operators_allowed_for_child_policies": ["@@none"]},
+			"tag_value": {"@@assign": ["100", "200*"]},
+			"enforced_for": {"@@assign": ["s3:bucket", "ecs:ALL_SUPPORTED", "foo:bar"]}
+		},
+		"team": {
+			"tag_key": {"@@assign": "Team"},
+			"tag_value": {"@@append": ["web"]},
+			"enforced_for": {"@@assign": ["foo:bar"]}
+		}
+	}}`
+	var Policy OrgTagPolicy
+	if err := json.Unmarshal([]byte(Document), &Policy); err != nil {
+		t.Fatal(err)
+	}
+	Rules, Warnings := ImportOrgTagPolicy(Policy)
+	Expected := []PolicyRule{{
+		Name:      "org-costcenter",
+		Resources: []string{"s3", "ecs-cluster", "ecs-service", "ecs-taskdefinition"},
+		Keys:      []PolicyKey{{Key: "CostCenter", Values: []string{"100", "200*"}}},
+	}}
+	if !reflect.DeepEqual(Rules, Expected) {
+		t.Errorf("got %+v, want %+v", Rules, Expected)
+	}
+	ExpectedWarnings := []string{
+		"tag costcenter: no scanner for resource type foo:bar",
+		"tag team: tag_value: @@append ignored, only @@assign is imported",
+		"tag team: no scanner for resource type foo:bar",
+		"tag team: skipped, none of its resource types are scanned",
+	}
+	if !reflect.DeepEqual(Warnings, ExpectedWarnings) {
+		t.Errorf("got warnings %q, want %q", Warnings, ExpectedWarnings)
+	}
+}
+
+func TestExportOrgTagPolicy(t *testing.T) {
+	Rules := []PolicyRule{
+		{Name: "a", Resources: []string{"ec2"}, Keys: []PolicyKey{{Key: "Team", Values: []string{"web"}}}},
+		{Name: "b", Resources: []string{"s3"}, Keys: []PolicyKey{{Key: "Team", Values: []string{"data"}, Aliases: []string{"team"}}}},
+	}
+	Document, Warnings := ExportOrgTagPolicy(Rules)
+	Tag := Document.Tags["team"]
+	if Tag.TagKey.Assign != "Team" || !reflect.DeepEqual(Tag.TagValue.Assign, []string{"web", "data"}) || !reflect.DeepEqual(Tag.EnforcedFor.Assign, []string{"ec2:instance", "s3:bucket"}) {
+		t.Errorf("got %+v", Tag)
+	}
+	if !reflect.DeepEqual(Warnings, []string{"policy b: key Team: aliases are not exported"}) {
+		t.Errorf("got warnings %q", Warnings)
+	}
+}
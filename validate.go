@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// InstanceStates are the EC2 instance states instancestates accepts.
+var InstanceStates = []string{"pending", "running", "shutting-down", "terminated", "stopping", "stopped"}
+
+func (Rule PolicyRule) Location() string {
+	return fmt.Sprintf("%s:%d", Rule.File, Rule.Line)
+}
+
+// FindLine returns the number of the first line from line From on that sets
+// Field to Value, either "field: value" or "- field: value", or a list item
+// "- value" when Field is empty. It returns From when there is none. yaml.v2
+// doesn't report where values are, so errors found after decoding use it to
+// point at the offending line.
+func FindLine(Source []byte, Field, Value string, From int) int {
+	for index, Line := range strings.Split(string(Source), "\n") {
+		if index+1 < From {
+			continue
+		}
+		Line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(Line), "-"))
+		if Field != "" {
+			if !strings.HasPrefix(Line, Field+":") {
+				continue
+			}
+			Line = strings.TrimSpace(strings.TrimPrefix(Line, Field+":"))
+		}
+		if strings.Trim(Line, `"'`) == Value {
+			return index + 1
+		}
+	}
+	return From
+}
+
+// ValidatePolicyRules checks the effective policies and returns every problem
+// found, each prefixed with the file and line it was found at.
+func ValidatePolicyRules(Rules []PolicyRule) []error {
+	var Errors []error
+	Sources := make(map[string][]byte)
+	for _, Rule := range Rules {
+		if _, ok := Sources[Rule.File]; !ok && Rule.File != "" {
+			Sources[Rule.File], _ = ioutil.ReadFile(Rule.File)
+		}
+		Source := Sources[Rule.File]
+		Errorf := func(Line int, format string, args ...interface{}) {
+			Errors = append(Errors, fmt.Errorf("%s:%d: policy %s: %s", Rule.File, Line, Rule.Name, fmt.Sprintf(format, args...)))
+		}
+		if Rule.Name == "" {
+			Errorf(Rule.Line, "name is empty")
+		}
+		if len(Rule.Resources) == 0 {
+			Errorf(Rule.Line, "resources is empty")
+		}
+		Seen := make(map[string]bool)
+		for _, ResourceName := range Rule.Resources {
+			Line := FindLine(Source, "", ResourceName, Rule.Line)
+			if _, ok := ResourceScanners[ResourceName]; !ok {
+				Errorf(Line, "unknown resource %q", ResourceName)
+			}
+			if Seen[ResourceName] {
+				Errorf(FindLine(Source, "", ResourceName, Line+1), "resource %q is listed more than once", ResourceName)
+			}
+			Seen[ResourceName] = true
+		}
+		if len(Rule.Keys) == 0 && len(Rule.Checks) == 0 {
+			Errorf(Rule.Line, "keys is empty")
+		}
+		Seen = make(map[string]bool)
+		for _, PolicyTag := range Rule.Keys {
+			Line := FindLine(Source, "", PolicyTag.Key, Rule.Line)
+			if PolicyTag.Key == "" {
+				Errorf(Rule.Line, "keys: key is empty")
+			}
+			if Seen[PolicyTag.Key] {
+				Errorf(FindLine(Source, "", PolicyTag.Key, Line+1), "key %q is listed more than once", PolicyTag.Key)
+			}
+			Seen[PolicyTag.Key] = true
+			for _, Alias := range PolicyTag.Aliases {
+				if Alias == PolicyTag.Key || Seen[Alias] {
+					Errorf(FindLine(Source, "", Alias, Line), "key %s: alias %q is already a key or alias", PolicyTag.Key, Alias)
+				}
+				Seen[Alias] = true
+			}
+			if SeverityRank(Rule.GetKeySeverity(PolicyTag)) == -1 {
+				Errorf(FindLine(Source, "severity", PolicyTag.Severity, Line), "key %s: unknown severity %q", PolicyTag.Key, PolicyTag.Severity)
+			}
+		}
+		Seen = make(map[string]bool)
+		for _, Check := range Rule.Checks {
+			Line := FindLine(Source, "name", Check.Name, Rule.Line)
+			if Check.Name == "" {
+				Errorf(Rule.Line, "checks: name is empty")
+			}
+			if Seen[Check.Name] {
+				Errorf(Line, "check %q is listed more than once", Check.Name)
+			}
+			Seen[Check.Name] = true
+			if _, err := Check.Program(); err != nil {
+				Errorf(FindLine(Source, "expression", Check.Expression, Line), "check %s: %v", Check.Name, err)
+			}
+			if SeverityRank(Rule.GetCheckSeverity(Check)) == -1 {
+				Errorf(FindLine(Source, "severity", Check.Severity, Line), "check %s: unknown severity %q", Check.Name, Check.Severity)
+			}
+		}
+		for _, State := range Rule.InstanceStates {
+			if !ContainsString(InstanceStates, State) {
+				Errorf(FindLine(Source, "", State, Rule.Line), "unknown instance state %q", State)
+			}
+		}
+		if SeverityRank(Rule.GetSeverity()) == -1 {
+			Errorf(FindLine(Source, "severity", Rule.Severity, Rule.Line), "unknown severity %q", Rule.Severity)
+		}
+		if !ContainsString(Modes, Rule.GetMode()) {
+			Errorf(FindLine(Source, "mode", Rule.Mode, Rule.Line), "unknown mode %q", Rule.Mode)
+		}
+		if _, err := ParseGracePeriod(Rule.Quarantine.After); err != nil {
+			Errorf(FindLine(Source, "after", Rule.Quarantine.After, Rule.Line), "quarantine: after: %v", err)
+		}
+		if len(Rule.Quarantine.Actions) > 0 && Rule.Quarantine.After == "" {
+			Errorf(FindLine(Source, "quarantine", "", Rule.Line), "quarantine: actions need an after")
+		}
+		for ResourceName, Action := range Rule.Quarantine.Actions {
+			Types, ok := QuarantineActions[Action]
+			if !ok {
+				Errorf(FindLine(Source, ResourceName, Action, Rule.Line), "quarantine: unknown action %q", Action)
+			} else if _, ok := Types[ResourceName]; !ok {
+				Errorf(FindLine(Source, ResourceName, Action, Rule.Line), "quarantine: %s can't be applied to %s", Action, ResourceName)
+			}
+		}
+		switch Rule.Inherit {
+		case "", InheritSatisfy, InheritCopy:
+		default:
+			Errorf(FindLine(Source, "inherit", Rule.Inherit, Rule.Line), "unknown inherit mode %q", Rule.Inherit)
+		}
+		if _, err := ParseGracePeriod(Rule.GracePeriod); err != nil {
+			Errorf(FindLine(Source, "graceperiod", Rule.GracePeriod, Rule.Line), "graceperiod: %v", err)
+		}
+	}
+	return Errors
+}
+
+// ContainsString is contains without the comparison logging, which would
+// flood the policy commands' output.
+func ContainsString(List []string, Value string) bool {
+	for _, Item := range List {
+		if Item == Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const ValidateTestPolicy = `policy:
+- name: "global"
+  resources:
+  - ec2
+  - s3
+  keys:
+  - Team
+  - Owner
+- name: 'broken'
+  resources:
+  - ec2
+  - ec2-unknown
+  keys:
+  - Team
+  - key: Owner
+    severity: urgent
+  mode: shout
+  quarantine:
+    actions:
+      ec2: stop
+`
+
+func TestFindLine(t *testing.T) {
+	Source := []byte(ValidateTestPolicy)
+	Cases := []struct {
+		Name  string
+		Field string
+		Value string
+		From  int
+		Line  int
+	}{
+		{"quoted field", "name", "global", 1, 2},
+		{"single quoted field", "name", "broken", 1, 9},
+		{"list item", "", "ec2", 1, 4},
+		{"list item from a line on", "", "ec2", 9, 11},
+		{"list item mapping", "key", "Owner", 9, 15},
+		{"field with another value", "mode", "warn", 9, 9},
+		{"missing value", "", "rds", 3, 3},
+	}
+	for _, Case := range Cases {
+		if Line := FindLine(Source, Case.Field, Case.Value, Case.From); Line != Case.Line {
+			t.Errorf("%s: got line %d, want %d", Case.Name, Line, Case.Line)
+		}
+	}
+}
+
+func TestValidatePolicyRules(t *testing.T) {
+	FilePath := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := ioutil.WriteFile(FilePath, []byte(ValidateTestPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Rules, err := LoadPolicyFile(FilePath, make(map[string]bool))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var Messages []string
+	for _, err := range ValidatePolicyRules(Rules) {
+		Messages = append(Messages, err.Error())
+	}
+	Expected := []string{
+		FilePath + `:12: policy broken: unknown resource "ec2-unknown"`,
+		FilePath + `:16: policy broken: key Owner: unknown severity "urgent"`,
+		FilePath + `:17: policy broken: unknown mode "shout"`,
+		FilePath + `:18: policy broken: quarantine: actions need an after`,
+	}
+	if !reflect.DeepEqual(Messages, Expected) {
+		t.Errorf("got %q, want %q", Messages, Expected)
+	}
+}